@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/nanoLeinz/librarium/internal/enum"
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/model/dto"
 	"github.com/nanoLeinz/librarium/internal/myerror"
@@ -13,16 +14,18 @@ import (
 )
 
 type AuthController struct {
-	MemberService service.MemberService
-	validator     *validator.Validate
-	log           *logrus.Logger
+	MemberService     service.MemberService
+	PermissionService service.PermissionService
+	validator         *validator.Validate
+	log               *logrus.Logger
 }
 
-func NewAuthController(service service.MemberService, validator *validator.Validate, log *logrus.Logger) *AuthController {
+func NewAuthController(service service.MemberService, permission service.PermissionService, validator *validator.Validate, log *logrus.Logger) *AuthController {
 	return &AuthController{
-		MemberService: service,
-		validator:     validator,
-		log:           log,
+		MemberService:     service,
+		PermissionService: permission,
+		validator:         validator,
+		log:               log,
 	}
 }
 
@@ -45,7 +48,7 @@ func (s *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 	s.log.WithField("function", "Register").Info("Request body decoded")
 
 	req.AccountStatus = "active"
-	req.Role = "member"
+	req.Role = enum.RoleMember.String()
 
 	err := s.validator.Struct(&req)
 	if err != nil {
@@ -160,7 +163,21 @@ func (s *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 		"memberID": member.ID,
 	}).Info("Password check successful")
 
-	token, err := helper.GenerateJWTToken(member)
+	permissions, err := s.PermissionService.GetByRole(r.Context(), member.Role)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "Login",
+			"memberID": member.ID,
+			"role":     member.Role,
+		}).WithError(err).Error("Failed to fetch role permissions")
+
+		response := myerror.ToWebResponse(err.(myerror.MyError))
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	token, err := helper.GenerateJWTToken(member, permissions)
 
 	if err != nil {
 		s.log.WithFields(logrus.Fields{
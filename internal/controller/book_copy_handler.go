@@ -112,7 +112,7 @@ func (s *BookCopyController) UpdateStatus(w http.ResponseWriter, r *http.Request
 
 	req := dto.BookCopyRequest{}
 	err = json.NewDecoder(r.Body).Decode(&req)
-	if err != nil || req.Status == "" {
+	if err != nil || (req.Status == "" && req.ReplacementCost == nil && req.Location == nil && req.Condition == nil && req.Barcode == nil) {
 		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid copy status")
 		response := dto.WebResponse{
 			Code:   http.StatusBadRequest,
@@ -256,6 +256,30 @@ func (s *BookCopyController) GetAll(w http.ResponseWriter, r *http.Request) {
 	helper.ResponseJSON(w, &response)
 }
 
+func (s *BookCopyController) GetShelfSearch(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "BookCopyController.GetShelfSearch")
+	logger.Info("received get shelf search request")
+
+	copies, err := s.copyService.GetShelfSearch(r.Context())
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get shelf search copies")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(copies),
+		"statusCode": http.StatusOK,
+	}).Info("shelf search copies fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: copies,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
 func (s *BookCopyController) GetCopyByCondition(w http.ResponseWriter, r *http.Request) {
 	logger := s.logWithCtx(r.Context(), "BookCopyController.GetCopyByCondition")
 
@@ -314,3 +338,98 @@ func (s *BookCopyController) GetCopyByCondition(w http.ResponseWriter, r *http.R
 	helper.ResponseJSON(w, &response)
 
 }
+
+func (s *BookCopyController) GetHistory(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "BookCopyController.GetHistory")
+
+	rawBookID := r.PathValue("bookID")
+	bookID, err := uuid.Parse(rawBookID)
+	if err != nil {
+		logger.WithField("rawBookID", rawBookID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid book id")
+		response := dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid book id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, &response)
+		return
+	}
+
+	rawID := r.PathValue("copyID")
+	copyID, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid copies")
+		response := dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid copies",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, &response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"bookID":     bookID,
+		"copyID":     copyID,
+		"statusCode": http.StatusOK,
+	}).Info("received get book copy history request")
+
+	events, err := s.copyService.GetHistory(r.Context(), bookID, uint(copyID))
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get book copy history")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(events),
+		"statusCode": http.StatusOK,
+	}).Info("book copy history fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: events,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *BookCopyController) GetCopyByBarcode(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "BookCopyController.GetCopyByBarcode")
+
+	barcode := r.URL.Query().Get("barcode")
+	if barcode == "" {
+		logger.WithField("statusCode", http.StatusBadRequest).Error("missing barcode")
+		response := dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "barcode is required",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, &response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"barcode":    barcode,
+		"statusCode": http.StatusOK,
+	}).Info("received get book copy by barcode request")
+
+	bookCopy, err := s.copyService.GetByBarcode(r.Context(), barcode)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get book copy by barcode")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"copyID":     bookCopy.ID,
+		"statusCode": http.StatusOK,
+	}).Info("book copy fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: bookCopy,
+	}
+	helper.ResponseJSON(w, &response)
+}
@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxICSUpload = 1 << 20
+
+type CalendarController struct {
+	log       *log.Logger
+	service   service.CalendarService
+	validator *validator.Validate
+}
+
+func NewCalendarController(log *log.Logger, service service.CalendarService, validator *validator.Validate) *CalendarController {
+	return &CalendarController{
+		log:       log,
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (s *CalendarController) logWithCtx(ctx context.Context, fun string) *log.Entry {
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	return s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": fun,
+	})
+}
+
+func (s *CalendarController) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CalendarController.GetCalendar")
+	logger.Info("received get calendar request")
+
+	res, err := s.service.Get(r.Context())
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get calendar")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithField("statusCode", http.StatusOK).Info("calendar fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CalendarController) SetOpeningHours(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CalendarController.SetOpeningHours")
+
+	rawWeekday := r.PathValue("weekday")
+	weekday, err := strconv.Atoi(rawWeekday)
+	if err != nil {
+		logger.WithField("rawWeekday", rawWeekday).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid weekday")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid weekday",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	rawReq := dto.OpeningHoursRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"weekday": weekday,
+		"closed":  rawReq.Closed,
+	}).Info("received set opening hours request")
+
+	if err := s.service.SetOpeningHours(r.Context(), weekday, &rawReq); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to set opening hours")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"weekday":    weekday,
+		"statusCode": http.StatusOK,
+	}).Info("opening hours set successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CalendarController) CreateClosure(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CalendarController.CreateClosure")
+
+	rawReq := dto.ClosureRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"startDate": rawReq.StartDate,
+		"endDate":   rawReq.EndDate,
+	}).Info("received create closure request")
+
+	res, err := s.service.CreateClosure(r.Context(), &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to create closure")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"closureID":  res.ID,
+		"statusCode": http.StatusOK,
+	}).Info("closure created successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CalendarController) DeleteClosure(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CalendarController.DeleteClosure")
+
+	rawID := r.PathValue("id")
+	closureID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid closure id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid closure id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("closureID", closureID).Info("received delete closure request")
+
+	if err := s.service.DeleteClosure(r.Context(), closureID); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to delete closure")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"closureID":  closureID,
+		"statusCode": http.StatusOK,
+	}).Info("closure deleted successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+// ImportClosures accepts the .ics file either as a multipart "file" field
+// or as the raw request body.
+func (s *CalendarController) ImportClosures(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CalendarController.ImportClosures")
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxICSUpload)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: missing ics file")
+			response := &dto.WebResponse{
+				Code:   http.StatusBadRequest,
+				Status: "invalid request",
+				Result: nil,
+			}
+			helper.ResponseJSON(w, response)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	logger.Info("received import closures request")
+
+	imported, err := s.service.ImportClosures(r.Context(), body)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to import closures")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"imported":   imported,
+		"statusCode": http.StatusOK,
+	}).Info("closures imported successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: dto.ClosureImportResponse{Imported: imported},
+	}
+	helper.ResponseJSON(w, &response)
+}
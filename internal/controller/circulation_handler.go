@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type CirculationController struct {
+	log     *log.Logger
+	service service.CirculationService
+}
+
+func NewCirculationController(log *log.Logger, service service.CirculationService) *CirculationController {
+	return &CirculationController{
+		log:     log,
+		service: service,
+	}
+}
+
+func (s *CirculationController) logWithCtx(ctx context.Context, fun string) *log.Entry {
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	return s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": fun,
+	})
+}
+
+func (s *CirculationController) StartSession(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationController.StartSession")
+
+	req := dto.PatronSessionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("memberBarcode", req.MemberBarcode).Info("received start patron session request")
+
+	res, err := s.service.StartSession(r.Context(), &req)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to start patron session")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"sessionID":  res.ID,
+		"statusCode": http.StatusOK,
+	}).Info("patron session started")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CirculationController) StartKioskSession(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationController.StartKioskSession")
+
+	req := dto.KioskSessionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("barcode", req.Barcode).Info("received start kiosk session request")
+
+	res, err := s.service.StartKioskSession(r.Context(), &req)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to start kiosk session")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"sessionID":  res.ID,
+		"statusCode": http.StatusOK,
+	}).Info("kiosk session started")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CirculationController) EndSession(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationController.EndSession")
+
+	rawID := r.PathValue("id")
+	ID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusNotFound).Error("invalid patron session id")
+		response := &dto.WebResponse{
+			Code:   http.StatusNotFound,
+			Status: "patron session not found",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("sessionID", ID).Info("received end patron session request")
+
+	if err := s.service.EndSession(r.Context(), ID); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to end patron session")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"sessionID":  ID,
+		"statusCode": http.StatusOK,
+	}).Info("patron session ended")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CirculationController) Scan(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationController.Scan")
+
+	req := dto.ScanRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"barcode":   req.Barcode,
+		"sessionID": req.SessionID,
+	}).Info("received scan request")
+
+	res, err := s.service.Scan(r.Context(), &req)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to process scan")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"action":     res.Action,
+		"statusCode": http.StatusOK,
+	}).Info("scan processed")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type CirculationPolicyController struct {
+	log       *log.Logger
+	service   service.CirculationPolicyService
+	validator *validator.Validate
+}
+
+func NewCirculationPolicyController(log *log.Logger, service service.CirculationPolicyService, validator *validator.Validate) *CirculationPolicyController {
+	return &CirculationPolicyController{
+		log:       log,
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (s *CirculationPolicyController) logWithCtx(ctx context.Context, fun string) *log.Entry {
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	return s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": fun,
+	})
+}
+
+func (s *CirculationPolicyController) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationPolicyController.GetPolicies")
+	logger.Info("received get circulation policies request")
+
+	res, err := s.service.GetAll(r.Context())
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get circulation policies")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("circulation policies fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CirculationPolicyController) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationPolicyController.CreatePolicy")
+
+	rawReq := dto.CirculationPolicyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"memberCategory": rawReq.MemberCategory,
+		"itemCategory":   rawReq.ItemCategory,
+	}).Info("received create circulation policy request")
+
+	res, err := s.service.Create(r.Context(), &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to create circulation policy")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"policyID":   res.ID,
+		"statusCode": http.StatusOK,
+	}).Info("circulation policy created successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CirculationPolicyController) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationPolicyController.UpdatePolicy")
+
+	rawID := r.PathValue("id")
+	policyID, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid circulation policy id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid circulation policy id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	rawReq := dto.CirculationPolicyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("policyID", policyID).Info("received update circulation policy request")
+
+	if err := s.service.Update(r.Context(), uint(policyID), &rawReq); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to update circulation policy")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"policyID":   policyID,
+		"statusCode": http.StatusOK,
+	}).Info("circulation policy updated successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *CirculationPolicyController) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "CirculationPolicyController.DeletePolicy")
+
+	rawID := r.PathValue("id")
+	policyID, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid circulation policy id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid circulation policy id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("policyID", policyID).Info("received delete circulation policy request")
+
+	if err := s.service.DeleteByID(r.Context(), uint(policyID)); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to delete circulation policy")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"policyID":   policyID,
+		"statusCode": http.StatusOK,
+	}).Info("circulation policy deleted successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
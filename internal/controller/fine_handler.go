@@ -0,0 +1,323 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type FineController struct {
+	log       *log.Logger
+	service   service.FineService
+	validator *validator.Validate
+}
+
+func NewFineController(log *log.Logger, service service.FineService, validator *validator.Validate) *FineController {
+	return &FineController{
+		log:       log,
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (s *FineController) logWithCtx(ctx context.Context, fun string) *log.Entry {
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	return s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": fun,
+	})
+}
+
+func (s *FineController) CreateFine(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "FineController.CreateFine")
+
+	rawReq := dto.FineRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"memberID": rawReq.MemberID,
+		"loanID":   rawReq.LoanID,
+		"amount":   rawReq.Amount,
+	}).Info("received create fine request")
+
+	res, err := s.service.Create(r.Context(), &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to create fine")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"fineID":     res.ID,
+		"statusCode": http.StatusOK,
+	}).Info("fine created successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *FineController) GetFineByID(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "FineController.GetFineByID")
+
+	rawID := r.PathValue("id")
+	fineID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid fine id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid fine id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("fineID", fineID).Info("received get fine by ID request")
+
+	res, err := s.service.GetByID(r.Context(), fineID)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get fine by ID")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"fineID":     fineID,
+		"statusCode": http.StatusOK,
+	}).Info("fine fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *FineController) GetAllFines(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "FineController.GetAllFines")
+
+	status := r.URL.Query().Get("status")
+	logger.WithField("status", status).Info("received get all fines request")
+
+	res, err := s.service.GetAll(r.Context(), status)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get all fines")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("all fines fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *FineController) GetMemberFines(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "FineController.GetMemberFines")
+
+	rawID := r.PathValue("id")
+	memberID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid member id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid member id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("memberID", memberID).Info("received get member fines request")
+
+	res, err := s.service.GetByMember(r.Context(), memberID)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get member fines")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"memberID":   memberID,
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("member fines fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *FineController) PayFine(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "FineController.PayFine")
+
+	rawID := r.PathValue("id")
+	fineID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid fine id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid fine id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	rawReq := dto.FinePaymentRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	staffID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithFields(log.Fields{
+		"fineID":  fineID,
+		"staffID": staffID,
+		"amount":  rawReq.Amount,
+	}).Info("received fine payment request")
+
+	res, err := s.service.Pay(r.Context(), fineID, staffID, &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to record fine payment")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"fineID":     fineID,
+		"status":     res.Status,
+		"statusCode": http.StatusOK,
+	}).Info("fine payment recorded successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *FineController) WaiveFine(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "FineController.WaiveFine")
+
+	rawID := r.PathValue("id")
+	fineID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid fine id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid fine id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	rawReq := dto.FineWaiveRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	staffID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithFields(log.Fields{
+		"fineID":  fineID,
+		"staffID": staffID,
+	}).Info("received waive fine request")
+
+	res, err := s.service.Waive(r.Context(), fineID, staffID, &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to waive fine")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"fineID":     fineID,
+		"statusCode": http.StatusOK,
+	}).Info("fine waived successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *FineController) GetMyFines(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "FineController.GetMyFines")
+
+	memberID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithField("memberID", memberID).Info("received get my fines request")
+
+	res, err := s.service.GetByMember(r.Context(), memberID)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get my fines")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"memberID":   memberID,
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("my fines fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
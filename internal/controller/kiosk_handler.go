@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type KioskController struct {
+	log       *log.Logger
+	service   service.KioskService
+	validator *validator.Validate
+}
+
+func NewKioskController(log *log.Logger, service service.KioskService, validator *validator.Validate) *KioskController {
+	return &KioskController{
+		log:       log,
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (s *KioskController) logWithCtx(ctx context.Context, fun string) *log.Entry {
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	return s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": fun,
+	})
+}
+
+// Verify checks a kiosk credential for middleware.ValidateKiosk.
+func (s *KioskController) Verify(ctx context.Context, id uuid.UUID, key string) error {
+	return s.service.Verify(ctx, id, key)
+}
+
+func (s *KioskController) CreateKiosk(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "KioskController.CreateKiosk")
+
+	rawReq := dto.KioskRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("name", rawReq.Name).Info("received create kiosk request")
+
+	res, err := s.service.Create(r.Context(), &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to create kiosk")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"kioskID":    res.ID,
+		"statusCode": http.StatusOK,
+	}).Info("kiosk created successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *KioskController) GetKiosks(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "KioskController.GetKiosks")
+	logger.Info("received get kiosks request")
+
+	res, err := s.service.GetAll(r.Context())
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get kiosks")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("kiosks fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *KioskController) DeactivateKiosk(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "KioskController.DeactivateKiosk")
+
+	rawID := r.PathValue("id")
+	kioskID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusNotFound).Error("invalid kiosk id")
+		response := &dto.WebResponse{
+			Code:   http.StatusNotFound,
+			Status: "kiosk not found",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithField("kioskID", kioskID).Info("received deactivate kiosk request")
+
+	if err := s.service.Deactivate(r.Context(), kioskID); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to deactivate kiosk")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"kioskID":    kioskID,
+		"statusCode": http.StatusOK,
+	}).Info("kiosk deactivated successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
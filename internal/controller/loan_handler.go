@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/model/dto"
 	"github.com/nanoLeinz/librarium/internal/myerror"
@@ -202,6 +203,13 @@ func (s *LoanController) GetLoanByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// members only see their own loans; to them anyone else's does not exist
+	if res.MemberID != helper.MemberIDFromContext(r.Context()) && !helper.HasPermission(r.Context(), enum.PermCirculationCheckout.String()) {
+		logger.WithField("statusCode", http.StatusNotFound).Warn("member tried to read someone else's loan")
+		helper.ResponseJSON(w, myerror.ToWebResponse(myerror.NewNotFoundError("loan")))
+		return
+	}
+
 	logger.WithFields(log.Fields{
 		"loanID":     loanID,
 		"statusCode": http.StatusOK,
@@ -237,3 +245,379 @@ func (s *LoanController) GetAllLoan(w http.ResponseWriter, r *http.Request) {
 	}
 	helper.ResponseJSON(w, &response)
 }
+
+func (s *LoanController) ReturnLoan(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "LoanController.ReturnLoan")
+
+	rawID := r.PathValue("id")
+	loanID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid loan id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid loan id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	rawReq := dto.LoanReturnRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil {
+			logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+			response := &dto.WebResponse{
+				Code:   http.StatusBadRequest,
+				Status: "invalid request",
+				Result: nil,
+			}
+			helper.ResponseJSON(w, response)
+			return
+		}
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":       loanID,
+		"damaged":      rawReq.Damaged,
+		"damageCharge": rawReq.DamageCharge,
+	}).Info("received return loan request")
+
+	res, err := s.service.Return(r.Context(), loanID, &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to return loan")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     loanID,
+		"copyStatus": res.CopyStatus,
+		"statusCode": http.StatusOK,
+	}).Info("loan returned successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *LoanController) BatchReturn(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "LoanController.BatchReturn")
+
+	rawReq := dto.BatchReturnRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"barcodes":   len(rawReq.Barcodes),
+		"copyIDs":    len(rawReq.CopyIDs),
+		"returnedAt": rawReq.ReturnedAt,
+	}).Info("received batch return request")
+
+	res, err := s.service.BatchReturn(r.Context(), &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to process batch return")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"summary":    res.Summary,
+		"statusCode": http.StatusOK,
+	}).Info("batch return processed")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *LoanController) RenewLoan(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "LoanController.RenewLoan")
+
+	rawID := r.PathValue("id")
+	loanID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid loan id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid loan id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	requesterID := helper.MemberIDFromContext(r.Context())
+	staff := helper.HasPermission(r.Context(), enum.PermCirculationCheckout.String())
+
+	logger.WithFields(log.Fields{
+		"loanID":      loanID,
+		"requesterID": requesterID,
+	}).Info("received renew loan request")
+
+	res, err := s.service.Renew(r.Context(), loanID, requesterID, staff)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to renew loan")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     loanID,
+		"dueDate":    res.DueDate,
+		"statusCode": http.StatusOK,
+	}).Info("loan renewed successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *LoanController) DeclareLost(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "LoanController.DeclareLost")
+
+	rawID := r.PathValue("id")
+	loanID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid loan id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid loan id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	requesterID := helper.MemberIDFromContext(r.Context())
+	staff := helper.HasPermission(r.Context(), enum.PermCirculationCheckout.String())
+
+	logger.WithFields(log.Fields{
+		"loanID":      loanID,
+		"requesterID": requesterID,
+	}).Info("received declare lost request")
+
+	res, err := s.service.DeclareLost(r.Context(), loanID, requesterID, staff)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to declare loan lost")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     loanID,
+		"copyStatus": res.CopyStatus,
+		"statusCode": http.StatusOK,
+	}).Info("loan declared lost successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *LoanController) ClaimReturned(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "LoanController.ClaimReturned")
+
+	rawID := r.PathValue("id")
+	loanID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid loan id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid loan id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	rawReq := dto.ClaimReturnedRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil {
+			logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+			response := &dto.WebResponse{
+				Code:   http.StatusBadRequest,
+				Status: "invalid request",
+				Result: nil,
+			}
+			helper.ResponseJSON(w, response)
+			return
+		}
+	}
+
+	logger.WithField("loanID", loanID).Info("received claim returned request")
+
+	res, err := s.service.ClaimReturned(r.Context(), loanID, &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to claim loan returned")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     loanID,
+		"statusCode": http.StatusOK,
+	}).Info("loan claimed returned successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *LoanController) ResolveClaim(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "LoanController.ResolveClaim")
+
+	rawID := r.PathValue("id")
+	loanID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid loan id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid loan id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	rawReq := dto.ClaimResolveRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     loanID,
+		"resolution": rawReq.Resolution,
+	}).Info("received resolve claim request")
+
+	res, err := s.service.ResolveClaim(r.Context(), loanID, &rawReq)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to resolve claim")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     loanID,
+		"resolution": res.Resolution,
+		"statusCode": http.StatusOK,
+	}).Info("claim resolved successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *LoanController) RecallForReservation(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "LoanController.RecallForReservation")
+
+	rawID := r.PathValue("id")
+	reservationID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid reservation id")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid reservation id",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	requesterID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithFields(log.Fields{
+		"reservationID": reservationID,
+		"requesterID":   requesterID,
+	}).Info("received recall request")
+
+	res, err := s.service.Recall(r.Context(), reservationID, &requesterID)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to recall loan")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     res.LoanID,
+		"newDueDate": res.NewDueDate,
+		"statusCode": http.StatusOK,
+	}).Info("loan recalled successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *LoanController) GetMyLoans(w http.ResponseWriter, r *http.Request) {
+	s.getMemberLoans(w, r, "LoanController.GetMyLoans", false)
+}
+
+func (s *LoanController) GetMyHistory(w http.ResponseWriter, r *http.Request) {
+	s.getMemberLoans(w, r, "LoanController.GetMyHistory", true)
+}
+
+func (s *LoanController) getMemberLoans(w http.ResponseWriter, r *http.Request, fun string, returned bool) {
+	logger := s.logWithCtx(r.Context(), fun)
+
+	memberID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithFields(log.Fields{
+		"memberID": memberID,
+		"returned": returned,
+	}).Info("received get member loans request")
+
+	loans, err := s.service.GetByMember(r.Context(), memberID, returned)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get member loans")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(loans),
+		"statusCode": http.StatusOK,
+	}).Info("member loans fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: loans,
+	}
+	helper.ResponseJSON(w, &response)
+}
@@ -150,3 +150,204 @@ func (s *MemberController) UpdateMember(w http.ResponseWriter, r *http.Request)
 
 	helper.ResponseJSON(w, response)
 }
+
+func (s *MemberController) GetAllMembers(w http.ResponseWriter, r *http.Request) {
+
+	s.log.WithField("function", "member_handler.GetAllMembers").Info("receive request GetAllMembers")
+
+	members, err := s.service.GetAllMembers(r.Context())
+
+	if err != nil {
+		s.log.WithError(err).Error("failed to fetch members from database")
+
+		response := myerror.ToWebResponse(err.(myerror.MyError))
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	response := &dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: members,
+	}
+
+	helper.ResponseJSON(w, response)
+}
+
+func (s *MemberController) ManageMember(w http.ResponseWriter, r *http.Request) {
+
+	memberID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.log.WithError(err).Error("invalid member id")
+
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid member id",
+			Result: nil,
+		}
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	s.log.WithFields(log.Fields{
+		"function": "member_handler.ManageMember",
+		"memberID": memberID,
+	}).Info("receive request ManageMember")
+
+	req := &dto.MemberManageRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || s.validator.Struct(req) != nil {
+		s.log.WithError(err).Error("Bad Request")
+
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Result: nil,
+		}
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	err = s.service.ManageMember(r.Context(), memberID, req)
+
+	if err != nil {
+		s.log.WithFields(log.Fields{
+			"function": "member_handler.ManageMember",
+			"memberID": memberID,
+		}).WithError(err).Error("Failed managing member")
+
+		response := myerror.ToWebResponse(err.(myerror.MyError))
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	response := &dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+
+	helper.ResponseJSON(w, response)
+}
+
+func (s *MemberController) GetMemberByBarcode(w http.ResponseWriter, r *http.Request) {
+
+	barcode := r.URL.Query().Get("barcode")
+
+	s.log.WithFields(log.Fields{
+		"function": "member_handler.GetMemberByBarcode",
+		"barcode":  barcode,
+	}).Info("receive request GetMemberByBarcode")
+
+	if barcode == "" {
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "barcode is required",
+			Result: nil,
+		}
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	member, err := s.service.GetMemberByBarcode(r.Context(), barcode)
+	if err != nil {
+		s.log.WithFields(log.Fields{
+			"function": "member_handler.GetMemberByBarcode",
+			"barcode":  barcode,
+		}).WithError(err).Error("Failed getting member by barcode")
+
+		response := myerror.ToWebResponse(err.(myerror.MyError))
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	response := &dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: member,
+	}
+
+	helper.ResponseJSON(w, response)
+}
+
+func (s *MemberController) SetMyPIN(w http.ResponseWriter, r *http.Request) {
+
+	memberID := helper.MemberIDFromContext(r.Context())
+
+	s.log.WithFields(log.Fields{
+		"function": "member_handler.SetMyPIN",
+		"memberID": memberID,
+	}).Info("receive request SetMyPIN")
+
+	s.setPIN(w, r, memberID)
+}
+
+func (s *MemberController) SetMemberPIN(w http.ResponseWriter, r *http.Request) {
+
+	memberID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.log.WithError(err).Error("invalid member id")
+
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid member id",
+			Result: nil,
+		}
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	s.log.WithFields(log.Fields{
+		"function": "member_handler.SetMemberPIN",
+		"memberID": memberID,
+	}).Info("receive request SetMemberPIN")
+
+	s.setPIN(w, r, memberID)
+}
+
+func (s *MemberController) setPIN(w http.ResponseWriter, r *http.Request, memberID uuid.UUID) {
+
+	req := &dto.PINRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || s.validator.Struct(req) != nil {
+		s.log.WithError(err).Error("Bad Request")
+
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "PIN must be 4 to 8 digits",
+			Result: nil,
+		}
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	err := s.service.SetPIN(r.Context(), memberID, req.PIN)
+
+	if err != nil {
+		s.log.WithFields(log.Fields{
+			"function": "member_handler.setPIN",
+			"memberID": memberID,
+		}).WithError(err).Error("Failed setting PIN")
+
+		response := myerror.ToWebResponse(err.(myerror.MyError))
+
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	response := &dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+
+	helper.ResponseJSON(w, response)
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type NotificationController struct {
+	log     *log.Logger
+	service service.NotificationService
+}
+
+func NewNotificationController(log *log.Logger, service service.NotificationService) *NotificationController {
+	return &NotificationController{
+		log:     log,
+		service: service,
+	}
+}
+
+func (s *NotificationController) logWithCtx(ctx context.Context, fun string) *log.Entry {
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	return s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": fun,
+	})
+}
+
+func (s *NotificationController) GetMyNotifications(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "NotificationController.GetMyNotifications")
+
+	memberID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithField("memberID", memberID).Info("received get my notifications request")
+
+	res, err := s.service.GetByMember(r.Context(), memberID)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get my notifications")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"memberID":   memberID,
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("my notifications fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *NotificationController) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "NotificationController.MarkNotificationRead")
+
+	rawID := r.PathValue("id")
+	ID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusNotFound).Error("invalid notification id")
+		response := &dto.WebResponse{
+			Code:   http.StatusNotFound,
+			Status: "notification not found",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	memberID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithFields(log.Fields{
+		"notificationID": ID,
+		"memberID":       memberID,
+	}).Info("received mark notification read request")
+
+	if err := s.service.MarkRead(r.Context(), ID, memberID); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to mark notification read")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"notificationID": ID,
+		"statusCode":     http.StatusOK,
+	}).Info("notification marked read")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type PermissionController struct {
+	log       *log.Logger
+	service   service.PermissionService
+	validator *validator.Validate
+}
+
+func NewPermissionController(log *log.Logger, service service.PermissionService, validator *validator.Validate) *PermissionController {
+	return &PermissionController{
+		log:       log,
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (s *PermissionController) logWithCtx(ctx context.Context, fun string) *log.Entry {
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	return s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": fun,
+	})
+}
+
+func (s *PermissionController) GetAll(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "PermissionController.GetAll")
+	logger.WithField("statusCode", http.StatusOK).Info("received get all role permissions request")
+
+	res, err := s.service.GetAll(r.Context())
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get role permissions")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("role permissions fetched successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *PermissionController) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "PermissionController.UpdateRole")
+
+	role := r.PathValue("role")
+
+	rawReq := dto.RolePermissionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil || s.validator.Struct(&rawReq) != nil {
+		logger.WithError(err).WithField("statusCode", http.StatusBadRequest).Error("invalid request: failed to decode body")
+		response := &dto.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "invalid request",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"role":        role,
+		"permissions": rawReq.Permissions,
+	}).Info("received update role permissions request")
+
+	if err := s.service.UpdateRole(r.Context(), role, &rawReq); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to update role permissions")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"role":       role,
+		"statusCode": http.StatusOK,
+	}).Info("role permissions updated successfully")
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
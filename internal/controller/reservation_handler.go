@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/model/dto"
 	"github.com/nanoLeinz/librarium/internal/myerror"
@@ -51,6 +52,12 @@ func (s *ReservationController) CreateReservation(w http.ResponseWriter, r *http
 		return
 	}
 
+	// members always reserve for themselves, staff may reserve on behalf
+	// of a member
+	if !helper.HasPermission(r.Context(), enum.PermCirculationCheckout.String()) || rawRq.MemberID == uuid.Nil {
+		rawRq.MemberID = helper.MemberIDFromContext(r.Context())
+	}
+
 	logger.WithFields(log.Fields{
 		"bookID":     rawRq.BookID,
 		"memberID":   rawRq.MemberID,
@@ -204,6 +211,14 @@ func (s *ReservationController) GetReservationByID(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// members only see their own reservations; to them anyone else's does
+	// not exist
+	if res.MemberID != helper.MemberIDFromContext(r.Context()) && !helper.HasPermission(r.Context(), enum.PermCirculationCheckout.String()) {
+		logger.WithField("statusCode", http.StatusNotFound).Warn("member tried to read someone else's reservation")
+		helper.ResponseJSON(w, myerror.ToWebResponse(myerror.NewNotFoundError("reservation")))
+		return
+	}
+
 	logger.WithFields(log.Fields{
 		"reservationID": res.ID,
 		"statusCode":    http.StatusOK,
@@ -241,3 +256,116 @@ func (s *ReservationController) GetAllReservation(w http.ResponseWriter, r *http
 	}
 	helper.ResponseJSON(w, &response)
 }
+
+func (s *ReservationController) GetBookQueue(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "Controller.GetBookQueue")
+
+	rawID := r.PathValue("id")
+	bookID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusNotFound).Error("invalid book id")
+		response := dto.WebResponse{
+			Code:   http.StatusNotFound,
+			Status: "Book Not Found",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, &response)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"bookID":     bookID,
+		"statusCode": http.StatusOK,
+	}).Info("received get book queue request")
+
+	res, err := s.serv.GetQueue(r.Context(), bookID)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get book queue")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("book queue fetched successfully")
+
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *ReservationController) GetMyReservations(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "Controller.GetMyReservations")
+
+	memberID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithField("memberID", memberID).Info("received get my reservations request")
+
+	res, err := s.serv.GetByMember(r.Context(), memberID)
+	if err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to get my reservations")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"count":      len(res),
+		"statusCode": http.StatusOK,
+	}).Info("my reservations fetched successfully")
+
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: res,
+	}
+	helper.ResponseJSON(w, &response)
+}
+
+func (s *ReservationController) CancelMyReservation(w http.ResponseWriter, r *http.Request) {
+	logger := s.logWithCtx(r.Context(), "Controller.CancelMyReservation")
+
+	rawID := r.PathValue("id")
+	ID, err := uuid.Parse(rawID)
+	if err != nil {
+		logger.WithField("rawID", rawID).WithError(err).WithField("statusCode", http.StatusNotFound).Error("invalid reservation id")
+		response := dto.WebResponse{
+			Code:   http.StatusNotFound,
+			Status: "Reservation Not Found",
+			Result: nil,
+		}
+		helper.ResponseJSON(w, &response)
+		return
+	}
+
+	memberID := helper.MemberIDFromContext(r.Context())
+
+	logger.WithFields(log.Fields{
+		"reservationID": ID,
+		"memberID":      memberID,
+	}).Info("received cancel my reservation request")
+
+	if err := s.serv.Cancel(r.Context(), ID, memberID); err != nil {
+		webRes := myerror.ToWebResponse(err.(myerror.MyError))
+		logger.WithError(err).WithField("statusCode", webRes.Code).Error("failed to cancel reservation")
+		helper.ResponseJSON(w, webRes)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"reservationID": ID,
+		"statusCode":    http.StatusOK,
+	}).Info("reservation cancelled successfully")
+
+	response := dto.WebResponse{
+		Code:   http.StatusOK,
+		Status: "success",
+		Result: nil,
+	}
+	helper.ResponseJSON(w, &response)
+}
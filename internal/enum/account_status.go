@@ -16,3 +16,13 @@ var accountStatusState = map[AccountStatus]string{
 func (s AccountStatus) String() string {
 	return accountStatusState[s]
 }
+
+func ParseAccountStatus(status string) (AccountStatus, bool) {
+	for k, v := range accountStatusState {
+		if v == status {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
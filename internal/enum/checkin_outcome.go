@@ -0,0 +1,24 @@
+package enum
+
+type CheckinOutcome int
+
+const (
+	_ CheckinOutcome = iota
+	CheckinReturned
+	CheckinHoldTrapped
+	CheckinNotOnLoan
+	CheckinUnknown
+	CheckinFailed
+)
+
+var checkinOutcomeState = map[CheckinOutcome]string{
+	CheckinReturned:    "returned",
+	CheckinHoldTrapped: "hold_trapped",
+	CheckinNotOnLoan:   "not_on_loan",
+	CheckinUnknown:     "unknown",
+	CheckinFailed:      "failed",
+}
+
+func (s CheckinOutcome) String() string {
+	return checkinOutcomeState[s]
+}
@@ -0,0 +1,20 @@
+package enum
+
+type CheckoutBlock int
+
+const (
+	_ CheckoutBlock = iota
+	MaxItemsBlock
+	OverdueBlock
+	UnpaidFinesBlock
+)
+
+var checkoutBlockState = map[CheckoutBlock]string{
+	MaxItemsBlock:    "max_items",
+	OverdueBlock:     "overdue_loans",
+	UnpaidFinesBlock: "unpaid_fines",
+}
+
+func (s CheckoutBlock) String() string {
+	return checkoutBlockState[s]
+}
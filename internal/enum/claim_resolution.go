@@ -0,0 +1,28 @@
+package enum
+
+type ClaimResolution int
+
+const (
+	_ ClaimResolution = iota
+	ClaimFound
+	ClaimLost
+)
+
+var claimResolutionState = map[ClaimResolution]string{
+	ClaimFound: "found",
+	ClaimLost:  "lost",
+}
+
+func (s ClaimResolution) String() string {
+	return claimResolutionState[s]
+}
+
+func ParseClaimResolution(resolution string) (ClaimResolution, bool) {
+	for k, v := range claimResolutionState {
+		if v == resolution {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
@@ -9,6 +9,9 @@ const (
 	ReservedCopy
 	DamagedCopy
 	LostCopy
+	InRepairCopy
+	InTransitCopy
+	WithdrawnCopy
 )
 
 var copyStatusState = map[CopyStatus]string{
@@ -17,8 +20,47 @@ var copyStatusState = map[CopyStatus]string{
 	ReservedCopy:  "reserved",
 	DamagedCopy:   "damaged",
 	LostCopy:      "lost",
+	InRepairCopy:  "in_repair",
+	InTransitCopy: "in_transit",
+	WithdrawnCopy: "withdrawn",
+}
+
+// copyTransitions lists the statuses each status may move to. Withdrawn
+// copies are out of the collection for good. Edges into loaned and reserved
+// are only taken by checkout, return and holds, never by hand. Edges out of
+// them may be taken by hand once no open loan or ready hold keeps the copy
+// there, to repair a stale status.
+var copyTransitions = map[CopyStatus][]CopyStatus{
+	AvailableCopy: {LoanedCopy, ReservedCopy, DamagedCopy, LostCopy, InRepairCopy, InTransitCopy, WithdrawnCopy},
+	LoanedCopy:    {AvailableCopy, ReservedCopy, DamagedCopy, LostCopy},
+	ReservedCopy:  {AvailableCopy, LoanedCopy, DamagedCopy, LostCopy, InTransitCopy},
+	DamagedCopy:   {InRepairCopy, LostCopy, WithdrawnCopy},
+	LostCopy:      {AvailableCopy, ReservedCopy, DamagedCopy, WithdrawnCopy},
+	InRepairCopy:  {AvailableCopy, ReservedCopy, LostCopy, WithdrawnCopy},
+	InTransitCopy: {AvailableCopy, ReservedCopy, LostCopy},
+	WithdrawnCopy: {},
 }
 
 func (s CopyStatus) String() string {
 	return copyStatusState[s]
 }
+
+func (s CopyStatus) CanTransitionTo(next CopyStatus) bool {
+	for _, v := range copyTransitions[s] {
+		if v == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ParseCopyStatus(status string) (CopyStatus, bool) {
+	for k, v := range copyStatusState {
+		if v == status {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,20 @@
+package enum
+
+type FineReason int
+
+const (
+	_ FineReason = iota
+	OverdueFineReason
+	LostFineReason
+	DamageFineReason
+)
+
+var fineReasonState = map[FineReason]string{
+	OverdueFineReason: "overdue",
+	LostFineReason:    "lost",
+	DamageFineReason:  "damage",
+}
+
+func (s FineReason) String() string {
+	return fineReasonState[s]
+}
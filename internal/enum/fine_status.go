@@ -0,0 +1,32 @@
+package enum
+
+type FineStatus int
+
+const (
+	_ FineStatus = iota
+	UnpaidFine
+	PartiallyPaidFine
+	PaidFine
+	WaivedFine
+)
+
+var fineStatusState = map[FineStatus]string{
+	UnpaidFine:        "unpaid",
+	PartiallyPaidFine: "partially_paid",
+	PaidFine:          "paid",
+	WaivedFine:        "waived",
+}
+
+func (s FineStatus) String() string {
+	return fineStatusState[s]
+}
+
+func ParseFineStatus(status string) (FineStatus, bool) {
+	for k, v := range fineStatusState {
+		if v == status {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
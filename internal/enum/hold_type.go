@@ -0,0 +1,30 @@
+package enum
+
+type HoldType int
+
+const (
+	_ HoldType = iota
+	TitleHold
+	CopyHold
+	WorkHold
+)
+
+var holdTypeState = map[HoldType]string{
+	TitleHold: "title",
+	CopyHold:  "copy",
+	WorkHold:  "work",
+}
+
+func (s HoldType) String() string {
+	return holdTypeState[s]
+}
+
+func ParseHoldType(holdType string) (HoldType, bool) {
+	for k, v := range holdTypeState {
+		if v == holdType {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
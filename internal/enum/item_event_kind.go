@@ -0,0 +1,42 @@
+package enum
+
+type ItemEventKind int
+
+const (
+	_ ItemEventKind = iota
+	StatusChangeEvent
+	CheckoutEvent
+	ReturnEvent
+	LocationMoveEvent
+	ConditionNoteEvent
+	ReplacementCostEvent
+	RenewEvent
+	RecallEvent
+	OverdueEvent
+	LostEvent
+	DeletedEvent
+	BarcodeChangeEvent
+	ClaimsReturnedEvent
+	ClaimResolvedEvent
+)
+
+var itemEventKindState = map[ItemEventKind]string{
+	StatusChangeEvent:    "status_change",
+	CheckoutEvent:        "checkout",
+	ReturnEvent:          "return",
+	LocationMoveEvent:    "location_move",
+	ConditionNoteEvent:   "condition_note",
+	ReplacementCostEvent: "replacement_cost",
+	RenewEvent:           "renew",
+	RecallEvent:          "recall",
+	OverdueEvent:         "overdue",
+	LostEvent:            "lost",
+	DeletedEvent:         "deleted",
+	BarcodeChangeEvent:   "barcode_change",
+	ClaimsReturnedEvent:  "claims_returned",
+	ClaimResolvedEvent:   "claim_resolved",
+}
+
+func (s ItemEventKind) String() string {
+	return itemEventKindState[s]
+}
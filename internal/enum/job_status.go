@@ -0,0 +1,18 @@
+package enum
+
+type JobStatus int
+
+const (
+	_ JobStatus = iota
+	SucceededJob
+	FailedJob
+)
+
+var jobState = map[JobStatus]string{
+	SucceededJob: "succeeded",
+	FailedJob:    "failed",
+}
+
+func (s JobStatus) String() string {
+	return jobState[s]
+}
@@ -7,12 +7,16 @@ const (
 	ActiveLoan
 	OverdueLoan
 	ReturnedLoan
+	LostLoan
+	ClaimsReturnedLoan
 )
 
 var loanStatusState = map[LoanStatus]string{
-	ActiveLoan:   "active",
-	OverdueLoan:  "overdue",
-	ReturnedLoan: "returned",
+	ActiveLoan:         "active",
+	OverdueLoan:        "overdue",
+	ReturnedLoan:       "returned",
+	LostLoan:           "lost",
+	ClaimsReturnedLoan: "claims_returned",
 }
 
 func (s LoanStatus) String() string {
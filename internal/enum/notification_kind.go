@@ -0,0 +1,16 @@
+package enum
+
+type NotificationKind int
+
+const (
+	_ NotificationKind = iota
+	RecallNotice
+)
+
+var notificationKindState = map[NotificationKind]string{
+	RecallNotice: "recall",
+}
+
+func (s NotificationKind) String() string {
+	return notificationKindState[s]
+}
@@ -0,0 +1,47 @@
+package enum
+
+type Permission int
+
+const (
+	_ Permission = iota
+	PermCatalogWrite
+	PermCirculationCheckout
+	PermCirculationManage
+	PermMembersManage
+	PermFinesWaive
+	PermFinesCollect
+	PermCirculationOverride
+)
+
+var permissionState = map[Permission]string{
+	PermCatalogWrite:        "catalog:write",
+	PermCirculationCheckout: "circulation:checkout",
+	PermCirculationManage:   "circulation:manage",
+	PermMembersManage:       "members:manage",
+	PermFinesWaive:          "fines:waive",
+	PermFinesCollect:        "fines:collect",
+	PermCirculationOverride: "circulation:override",
+}
+
+func (s Permission) String() string {
+	return permissionState[s]
+}
+
+func ParsePermission(perm string) (Permission, bool) {
+	for k, v := range permissionState {
+		if v == perm {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
+
+func AllPermissions() []Permission {
+	perms := make([]Permission, 0, len(permissionState))
+	for i := PermCatalogWrite; int(i) <= len(permissionState); i++ {
+		perms = append(perms, i)
+	}
+
+	return perms
+}
@@ -7,14 +7,28 @@ const (
 	PendingReserv
 	FulfilledReserv
 	CancelledReserv
+	ReadyReserv
+	ExpiredReserv
 )
 
 var reservString = map[ReservStatus]string{
 	PendingReserv:   "pending",
 	FulfilledReserv: "fulfilled",
 	CancelledReserv: "cancelled",
+	ReadyReserv:     "ready",
+	ExpiredReserv:   "expired",
 }
 
 func (s ReservStatus) String() string {
 	return reservString[s]
 }
+
+func ParseReservStatus(status string) (ReservStatus, bool) {
+	for k, v := range reservString {
+		if v == status {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
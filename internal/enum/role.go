@@ -6,13 +6,25 @@ const (
 	_ Role = iota
 	RoleMember
 	RoleAdmin
+	RoleLibrarian
 )
 
 var roleState = map[Role]string{
-	RoleMember: "member",
-	RoleAdmin:  "admin",
+	RoleMember:    "member",
+	RoleAdmin:     "admin",
+	RoleLibrarian: "librarian",
 }
 
 func (s Role) String() string {
 	return roleState[s]
 }
+
+func ParseRole(role string) (Role, bool) {
+	for k, v := range roleState {
+		if v == role {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
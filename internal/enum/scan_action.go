@@ -0,0 +1,18 @@
+package enum
+
+type ScanAction int
+
+const (
+	_ ScanAction = iota
+	ScanCheckout
+	ScanCheckin
+)
+
+var scanActionState = map[ScanAction]string{
+	ScanCheckout: "checkout",
+	ScanCheckin:  "checkin",
+}
+
+func (s ScanAction) String() string {
+	return scanActionState[s]
+}
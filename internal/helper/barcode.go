@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+// Barcodes are a prefix followed by BARCODE_DIGITS random digits and,
+// unless BARCODE_CHECK_DIGIT is false, a trailing Luhn check digit. Items
+// and member cards use different prefixes so a scan can never be read as
+// the wrong kind.
+
+func ItemBarcodePrefix() string {
+	return GetEnv("BARCODE_ITEM_PREFIX", "31")
+}
+
+func MemberBarcodePrefix() string {
+	return GetEnv("BARCODE_MEMBER_PREFIX", "21")
+}
+
+func NewBarcode(prefix string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+
+	for range GetEnvInt("BARCODE_DIGITS", 10) {
+		b.WriteByte(byte('0' + rand.IntN(10)))
+	}
+
+	if GetEnvBool("BARCODE_CHECK_DIGIT", true) {
+		b.WriteByte(luhnDigit(b.String()))
+	}
+
+	return b.String()
+}
+
+// ValidBarcode reports whether code carries prefix, is otherwise all
+// digits and, when check digits are on, ends in the right one. Barcodes
+// printed before the digit count changed are still accepted.
+func ValidBarcode(prefix string, code string) bool {
+	body, ok := strings.CutPrefix(code, prefix)
+	if !ok || body == "" {
+		return false
+	}
+
+	for _, c := range body {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	if !GetEnvBool("BARCODE_CHECK_DIGIT", true) {
+		return true
+	}
+
+	last := len(code) - 1
+	return len(body) > 1 && code[last] == luhnDigit(code[:last])
+}
+
+// luhnDigit computes the Luhn check digit over the digits in s, skipping
+// any non-digit prefix characters.
+func luhnDigit(s string) byte {
+	sum := 0
+	double := true
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
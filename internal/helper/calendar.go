@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"time"
+
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// maxClosedRun bounds the search for the next open day so a fully closed
+// calendar cannot loop forever.
+const maxClosedRun = 366
+
+type Calendar struct {
+	Hours    []model.OpeningHours
+	Closures []model.Closure
+}
+
+func (s Calendar) IsOpen(day time.Time) bool {
+	for _, v := range s.Hours {
+		if v.Weekday == int(day.Weekday()) && v.Closed {
+			return false
+		}
+	}
+
+	date := day.Format(dateLayout)
+	for _, v := range s.Closures {
+		if date >= v.StartDate.UTC().Format(dateLayout) && date <= v.EndDate.UTC().Format(dateLayout) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NextOpenDay returns day itself when the library is open on it, otherwise
+// the same time of day on the first open day after it.
+func (s Calendar) NextOpenDay(day time.Time) time.Time {
+	for i := 0; i < maxClosedRun; i++ {
+		if s.IsOpen(day) {
+			return day
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
+
+// PrevOpenDay returns day itself when the library is open on it, otherwise
+// the same time of day on the last open day before it.
+func (s Calendar) PrevOpenDay(day time.Time) time.Time {
+	for i := 0; i < maxClosedRun; i++ {
+		if s.IsOpen(day) {
+			return day
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return day
+}
+
+// OpenDaysOverdue counts the open days after the due date up to and
+// including the return date.
+func (s Calendar) OpenDaysOverdue(dueDate time.Time, returnedAt time.Time) int {
+	total := DaysOverdue(dueDate, returnedAt)
+
+	open := 0
+	for i := 1; i <= total; i++ {
+		if s.IsOpen(dueDate.AddDate(0, 0, i)) {
+			open++
+		}
+	}
+
+	return open
+}
+
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(dateLayout, value)
+}
@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"time"
+
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type CirculationPolicy struct {
+	LoanDays    int
+	MaxRenewals int
+	MaxItems    int
+	Fine        FineRate
+}
+
+func (s CirculationPolicy) LoanPeriod() time.Duration {
+	return time.Duration(s.LoanDays) * 24 * time.Hour
+}
+
+func DefaultCirculationPolicy() CirculationPolicy {
+	return CirculationPolicy{
+		LoanDays:    GetEnvInt("LOAN_PERIOD_DAYS", 7),
+		MaxRenewals: GetEnvInt("LOAN_MAX_RENEWALS", 2),
+		MaxItems:    GetEnvInt("LOAN_MAX_ITEMS", 5),
+		Fine:        DefaultFineRate(),
+	}
+}
+
+// ResolveCirculationPolicy picks the most specific rule for the member
+// category, item category and genre, an empty rule field matching anything.
+// A member category match outranks an item category match, which outranks
+// a genre match.
+func ResolveCirculationPolicy(rules []model.CirculationPolicy, memberCategory string, itemCategory string, genre string, fallback CirculationPolicy) CirculationPolicy {
+	best := -1
+	policy := fallback
+
+	for _, v := range rules {
+		if (v.MemberCategory != "" && v.MemberCategory != memberCategory) ||
+			(v.ItemCategory != "" && v.ItemCategory != itemCategory) ||
+			(v.Genre != "" && v.Genre != genre) {
+			continue
+		}
+
+		score := 0
+		if v.MemberCategory != "" {
+			score += 4
+		}
+		if v.ItemCategory != "" {
+			score += 2
+		}
+		if v.Genre != "" {
+			score += 1
+		}
+
+		if score > best {
+			best = score
+			policy = CirculationPolicy{
+				LoanDays:    v.LoanDays,
+				MaxRenewals: v.MaxRenewals,
+				MaxItems:    v.MaxItems,
+				Fine: FineRate{
+					DailyRate: v.DailyFineRate,
+					GraceDays: v.GraceDays,
+					MaxAmount: v.MaxFine,
+				},
+			}
+		}
+	}
+
+	return policy
+}
@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func GetEnvInt(key string, fallback int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+
+	val, err := strconv.Atoi(raw)
+	if err != nil {
+		log.WithError(err).Errorf("Invalid %s env var, falling back to %d", key, fallback)
+		return fallback
+	}
+
+	return val
+}
+
+func GetEnvFloat(key string, fallback float64) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+
+	val, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.WithError(err).Errorf("Invalid %s env var, falling back to %v", key, fallback)
+		return fallback
+	}
+
+	return val
+}
+
+func GetEnvBool(key string, fallback bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+
+	val, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.WithError(err).Errorf("Invalid %s env var, falling back to %v", key, fallback)
+		return fallback
+	}
+
+	return val
+}
+
+func GetEnv(key string, fallback string) string {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+
+	return raw
+}
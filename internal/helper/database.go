@@ -9,6 +9,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -59,9 +60,113 @@ func InitDatabase() *gorm.DB {
 func AutoMigrateModels(db *gorm.DB) {
 	db.AutoMigrate(&model.Author{})
 	db.AutoMigrate(&model.Loan{})
+	db.AutoMigrate(&model.LoanRenewal{})
+	db.AutoMigrate(&model.Recall{})
+	db.AutoMigrate(&model.CheckoutOverride{})
+	db.AutoMigrate(&model.Notification{})
 	db.AutoMigrate(&model.BookCopy{})
+	db.AutoMigrate(&model.ItemEvent{})
 	db.AutoMigrate(&model.Book{})
 	db.AutoMigrate(&model.Fine{})
+	db.AutoMigrate(&model.FinePayment{})
+	// policies gained a genre dimension, so the scope index is rebuilt
+	if db.Migrator().HasIndex(&model.CirculationPolicy{}, "idx_circulation_policy_scope") {
+		db.Migrator().DropIndex(&model.CirculationPolicy{}, "idx_circulation_policy_scope")
+	}
+	db.AutoMigrate(&model.CirculationPolicy{})
+	migrateFineRules(db)
+	db.AutoMigrate(&model.OpeningHours{})
+	db.AutoMigrate(&model.Closure{})
+	db.AutoMigrate(&model.JobRun{})
 	db.AutoMigrate(&model.Member{})
 	db.AutoMigrate(&model.Reservation{})
+	// reservations placed before hold types queue on their title
+	db.Exec("UPDATE reservations SET queue_key = 'title:' || book_id::text WHERE queue_key IS NULL OR queue_key = ''")
+	db.AutoMigrate(&model.RolePermission{})
+	db.AutoMigrate(&model.RolePermissionSeed{})
+	db.AutoMigrate(&model.PatronSession{})
+	db.AutoMigrate(&model.Kiosk{})
+	backfillBarcodes(db)
+}
+
+// migrateFineRules moves the per member category and genre fine rules that
+// circulation policies replaced into the policy matrix, then drops their
+// table. Each rule becomes a policy for the same member category and genre,
+// taking its loan terms from the policy the scope resolved to beforehand.
+// A policy already set for the scope wins over the rule.
+func migrateFineRules(db *gorm.DB) {
+	if !db.Migrator().HasTable("fine_rules") {
+		return
+	}
+
+	type fineRule struct {
+		MemberCategory string
+		Genre          string
+		DailyRate      float64
+		GraceDays      int
+		MaxAmount      float64
+	}
+
+	rules := []fineRule{}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("fine_rules").Where("deleted_at IS NULL").Find(&rules).Error; err != nil {
+			return err
+		}
+
+		policies := []model.CirculationPolicy{}
+		if err := tx.Find(&policies).Error; err != nil {
+			return err
+		}
+
+		for _, v := range rules {
+			terms := ResolveCirculationPolicy(policies, v.MemberCategory, "", v.Genre, DefaultCirculationPolicy())
+
+			policy := model.CirculationPolicy{
+				MemberCategory: v.MemberCategory,
+				Genre:          v.Genre,
+				LoanDays:       terms.LoanDays,
+				MaxRenewals:    terms.MaxRenewals,
+				MaxItems:       terms.MaxItems,
+				DailyFineRate:  v.DailyRate,
+				GraceDays:      v.GraceDays,
+				MaxFine:        v.MaxAmount,
+			}
+
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&policy).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Migrator().DropTable("fine_rules")
+	})
+
+	if err != nil {
+		log.WithError(err).Error("failed migrating fine rules to circulation policies")
+		return
+	}
+
+	log.WithField("rules", len(rules)).Info("fine rules migrated to circulation policies")
+}
+
+// backfillBarcodes labels copies and members created before barcodes.
+func backfillBarcodes(db *gorm.DB) {
+	copies := []model.BookCopy{}
+	db.Where("barcode IS NULL").Find(&copies)
+	for _, v := range copies {
+		db.Model(&v).Update("barcode", NewBarcode(ItemBarcodePrefix()))
+	}
+
+	members := []model.Member{}
+	db.Where("barcode IS NULL").Find(&members)
+	for _, v := range members {
+		db.Model(&v).Update("barcode", NewBarcode(MemberBarcodePrefix()))
+	}
+
+	if len(copies) > 0 || len(members) > 0 {
+		log.WithFields(log.Fields{
+			"copies":  len(copies),
+			"members": len(members),
+		}).Info("barcodes backfilled")
+	}
 }
@@ -0,0 +1,48 @@
+package helper
+
+import (
+	"math"
+	"time"
+)
+
+type FineRate struct {
+	DailyRate float64
+	GraceDays int
+	MaxAmount float64
+}
+
+func DefaultFineRate() FineRate {
+	return FineRate{
+		DailyRate: GetEnvFloat("FINE_DAILY_RATE", 1000),
+		GraceDays: GetEnvInt("FINE_GRACE_DAYS", 0),
+		MaxAmount: GetEnvFloat("FINE_MAX_AMOUNT", 0),
+	}
+}
+
+func DaysOverdue(dueDate time.Time, returnedAt time.Time) int {
+	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.UTC)
+	ret := time.Date(returnedAt.Year(), returnedAt.Month(), returnedAt.Day(), 0, 0, 0, 0, time.UTC)
+
+	days := int(ret.Sub(due).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+
+	return days
+}
+
+// CalculateOverdueFine charges every overdue day past the grace period,
+// capped at MaxAmount when it is set.
+func CalculateOverdueFine(rate FineRate, daysOverdue int) float64 {
+	chargeable := daysOverdue - rate.GraceDays
+	if chargeable <= 0 || rate.DailyRate <= 0 {
+		return 0
+	}
+
+	amount := float64(chargeable) * rate.DailyRate
+	if rate.MaxAmount > 0 && amount > rate.MaxAmount {
+		amount = rate.MaxAmount
+	}
+
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,64 @@
+package helper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDaysOverdue(t *testing.T) {
+	due := time.Date(2024, 3, 10, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		returnedAt time.Time
+		want       int
+	}{
+		{"returned early", due.AddDate(0, 0, -3), 0},
+		{"returned on due date", due.Add(-5 * time.Hour), 0},
+		{"returned later on due date", due.Add(6 * time.Hour), 0},
+		{"one day late", time.Date(2024, 3, 11, 0, 1, 0, 0, time.UTC), 1},
+		{"many days late", due.AddDate(0, 0, 30), 30},
+		{"across month end", time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC), 23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DaysOverdue(due, tt.returnedAt); got != tt.want {
+				t.Errorf("DaysOverdue() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateOverdueFine(t *testing.T) {
+	tests := []struct {
+		name string
+		rate FineRate
+		days int
+		want float64
+	}{
+		{"no days overdue", FineRate{DailyRate: 1000}, 0, 0},
+		{"negative days overdue", FineRate{DailyRate: 1000}, -2, 0},
+		{"one day", FineRate{DailyRate: 1000}, 1, 1000},
+		{"several days", FineRate{DailyRate: 1000}, 7, 7000},
+		{"within grace period", FineRate{DailyRate: 1000, GraceDays: 3}, 3, 0},
+		{"past grace period", FineRate{DailyRate: 1000, GraceDays: 3}, 5, 2000},
+		{"under cap", FineRate{DailyRate: 1000, MaxAmount: 10000}, 4, 4000},
+		{"at cap", FineRate{DailyRate: 1000, MaxAmount: 10000}, 10, 10000},
+		{"over cap", FineRate{DailyRate: 1000, MaxAmount: 10000}, 45, 10000},
+		{"cap with grace period", FineRate{DailyRate: 1000, GraceDays: 2, MaxAmount: 5000}, 20, 5000},
+		{"zero cap means uncapped", FineRate{DailyRate: 1000, MaxAmount: 0}, 100, 100000},
+		{"higher rate", FineRate{DailyRate: 2500}, 4, 10000},
+		{"fractional rate rounds to cents", FineRate{DailyRate: 0.335}, 3, 1.01},
+		{"zero rate", FineRate{DailyRate: 0}, 10, 0},
+		{"negative rate", FineRate{DailyRate: -500}, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateOverdueFine(tt.rate, tt.days); got != tt.want {
+				t.Errorf("CalculateOverdueFine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
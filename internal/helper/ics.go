@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+var ErrInvalidICS = errors.New("invalid icalendar data")
+
+// ParseICSClosures reads every VEVENT of an iCalendar stream as a closure.
+// All-day events use an exclusive DTEND as RFC 5545 specifies, timed
+// events close the library for every date they touch.
+func ParseICSClosures(r io.Reader) ([]model.Closure, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	closures := []model.Closure{}
+
+	var (
+		inEvent    bool
+		start, end time.Time
+		allDay     bool
+		summary    string
+		uid        string
+	)
+
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			inEvent = true
+			start, end, allDay, summary, uid = time.Time{}, time.Time{}, false, "", ""
+			continue
+		case line == "END:VEVENT":
+			inEvent = false
+			if start.IsZero() {
+				return nil, ErrInvalidICS
+			}
+
+			if end.IsZero() {
+				end = start
+			} else if allDay && end.After(start) {
+				end = end.AddDate(0, 0, -1)
+			}
+
+			closure := model.Closure{
+				StartDate: start,
+				EndDate:   end,
+				Reason:    summary,
+			}
+			if uid != "" {
+				value := uid
+				closure.UID = &value
+			}
+
+			closures = append(closures, closure)
+			continue
+		}
+
+		if !inEvent {
+			continue
+		}
+
+		name, params, value, ok := splitICSLine(line)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "DTSTART":
+			start, allDay, err = parseICSDate(params, value)
+		case "DTEND":
+			end, _, err = parseICSDate(params, value)
+		case "SUMMARY":
+			summary = unescapeICS(value)
+		case "UID":
+			uid = value
+		}
+
+		if err != nil {
+			return nil, ErrInvalidICS
+		}
+	}
+
+	if inEvent {
+		return nil, ErrInvalidICS
+	}
+
+	return closures, nil
+}
+
+func unfoldICS(r io.Reader) ([]string, error) {
+	lines := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func splitICSLine(line string) (string, string, string, bool) {
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return "", "", "", false
+	}
+
+	head, value := line[:idx], line[idx+1:]
+	name, params, _ := strings.Cut(head, ";")
+
+	return strings.ToUpper(name), strings.ToUpper(params), value, true
+}
+
+// parseICSDate returns the calendar date of a DATE or DATE-TIME value and
+// reports whether it was a plain date.
+func parseICSDate(params string, value string) (time.Time, bool, error) {
+	if strings.Contains(params, "VALUE=DATE") && !strings.Contains(params, "VALUE=DATE-TIME") || len(value) == 8 {
+		date, err := time.Parse("20060102", value)
+		return date, true, err
+	}
+
+	layout := "20060102T150405"
+	if strings.HasSuffix(value, "Z") {
+		layout += "Z"
+	}
+
+	moment, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return time.Date(moment.Year(), moment.Month(), moment.Day(), 0, 0, 0, 0, time.UTC), false, nil
+}
+
+func unescapeICS(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
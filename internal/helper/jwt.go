@@ -12,13 +12,14 @@ import (
 )
 
 type JWTClaims struct {
-	MemberID string
-	Role     string
-	Email    string
+	MemberID    string
+	Role        string
+	Email       string
+	Permissions []string
 	jwt.RegisteredClaims
 }
 
-func GenerateJWTToken(member *dto.MemberResponse) (string, error) {
+func GenerateJWTToken(member *dto.MemberResponse, permissions []string) (string, error) {
 
 	expiryStr := os.Getenv("EXPIRYINMINUTE")
 	expiry, err := strconv.Atoi(expiryStr)
@@ -30,9 +31,10 @@ func GenerateJWTToken(member *dto.MemberResponse) (string, error) {
 	secretKey := os.Getenv("SECRETJWT")
 
 	claims := JWTClaims{
-		MemberID: member.ID.String(),
-		Email:    member.Email,
-		Role:     member.Role,
+		MemberID:    member.ID.String(),
+		Email:       member.Email,
+		Role:        member.Role,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expiry) * time.Minute)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Reservations wait in one queue per thing they can be filled by: a
+// title, a single copy, or every edition of a work.
+
+func TitleQueueKey(bookID uuid.UUID) string {
+	return "title:" + bookID.String()
+}
+
+func CopyQueueKey(copyID uint) string {
+	return "copy:" + strconv.FormatUint(uint64(copyID), 10)
+}
+
+func WorkQueueKey(workKey string) string {
+	return "work:" + workKey
+}
+
+// CopyQueueKeys lists every queue a copy can fill, in the order their
+// locks are taken.
+func CopyQueueKeys(copyID uint, bookID uuid.UUID, workKey string) []string {
+	keys := []string{CopyQueueKey(copyID), TitleQueueKey(bookID)}
+	if workKey != "" {
+		keys = append(keys, WorkQueueKey(workKey))
+	}
+
+	return keys
+}
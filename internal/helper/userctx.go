@@ -1,3 +1,65 @@
 package helper
 
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
 type KeyCon string
+
+func MemberIDFromContext(ctx context.Context) uuid.UUID {
+	memberDatas, _ := ctx.Value("memberDatas").(map[string]any)
+	memberID, _ := memberDatas["memberID"].(uuid.UUID)
+
+	return memberID
+}
+
+// ActorIDFromContext returns the authenticated member, or nil when the
+// call did not come from a request, e.g. a scheduled job.
+func ActorIDFromContext(ctx context.Context) *uuid.UUID {
+	memberID := MemberIDFromContext(ctx)
+	if memberID == uuid.Nil {
+		return nil
+	}
+
+	return &memberID
+}
+
+func RoleFromContext(ctx context.Context) string {
+	memberDatas, _ := ctx.Value("memberDatas").(map[string]any)
+	role, _ := memberDatas["role"].(string)
+
+	return role
+}
+
+func HasPermission(ctx context.Context, perm string) bool {
+	memberDatas, _ := ctx.Value("memberDatas").(map[string]any)
+	perms, _ := memberDatas["permissions"].([]string)
+
+	return slices.Contains(perms, perm)
+}
+
+// WithMember acts as memberID, without any staff permissions, for the rest
+// of ctx. Kiosks use it once a member has signed in at the kiosk.
+func WithMember(ctx context.Context, memberID uuid.UUID) context.Context {
+	return context.WithValue(ctx, "memberDatas", map[string]any{
+		"memberID": memberID,
+	})
+}
+
+func WithKiosk(ctx context.Context, kioskID uuid.UUID) context.Context {
+	return context.WithValue(ctx, KeyCon("kioskID"), kioskID)
+}
+
+// KioskIDFromContext returns the kiosk making the request, or nil when it
+// did not come from one.
+func KioskIDFromContext(ctx context.Context) *uuid.UUID {
+	kioskID, ok := ctx.Value(KeyCon("kioskID")).(uuid.UUID)
+	if !ok {
+		return nil
+	}
+
+	return &kioskID
+}
@@ -76,8 +76,9 @@ func ValidateJWT(next http.Handler) http.Handler {
 		}
 
 		vals := map[string]any{
-			"memberID": memberID,
-			"role":     role,
+			"memberID":    memberID,
+			"role":        role,
+			"permissions": claims.Permissions,
 		}
 
 		ctx := context.WithValue(r.Context(), "memberDatas", vals)
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+func RequireRole(roles ...enum.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			memberDatas, _ := r.Context().Value("memberDatas").(map[string]any)
+			rawRole, _ := memberDatas["role"].(string)
+
+			role, ok := enum.ParseRole(rawRole)
+
+			if ok {
+				for _, v := range roles {
+					if v == role {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+
+			log.WithFields(log.Fields{
+				"memberID": memberDatas["memberID"],
+				"role":     rawRole,
+				"path":     r.URL.Path,
+			}).Warn("role not allowed to access route")
+
+			response := &dto.WebResponse{
+				Code:   http.StatusForbidden,
+				Status: "forbidden",
+				Result: nil,
+			}
+
+			helper.ResponseJSON(w, response)
+		})
+	}
+}
+
+func RequirePermission(perm enum.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			memberDatas, _ := r.Context().Value("memberDatas").(map[string]any)
+
+			if helper.HasPermission(r.Context(), perm.String()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			log.WithFields(log.Fields{
+				"memberID":   memberDatas["memberID"],
+				"role":       memberDatas["role"],
+				"permission": perm.String(),
+				"path":       r.URL.Path,
+			}).Warn("missing permission to access route")
+
+			response := &dto.WebResponse{
+				Code:   http.StatusForbidden,
+				Status: "forbidden",
+				Result: nil,
+			}
+
+			helper.ResponseJSON(w, response)
+		})
+	}
+}
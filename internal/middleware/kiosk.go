@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+// ValidateKiosk authenticates a kiosk from "Authorization: Kiosk <id>.<key>"
+// and adds its ID to the request context.
+func ValidateKiosk(verify func(ctx context.Context, id uuid.UUID, key string) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			credential, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Kiosk ")
+			rawID, key, found := strings.Cut(credential, ".")
+			kioskID, err := uuid.Parse(rawID)
+
+			if !ok || !found || err != nil || key == "" {
+				log.Warn("kiosk credential not found or wrong format")
+
+				response := &dto.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "kiosk credential not found or wrong format",
+					Result: nil,
+				}
+
+				helper.ResponseJSON(w, response)
+				return
+			}
+
+			if err := verify(r.Context(), kioskID, key); err != nil {
+				log.WithField("kioskID", kioskID).Warn("kiosk credential validation failed")
+
+				response := &dto.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "invalid kiosk credential",
+					Result: nil,
+				}
+
+				helper.ResponseJSON(w, response)
+				return
+			}
+
+			ctx := helper.WithKiosk(r.Context(), kioskID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -13,6 +13,8 @@ type Book struct {
 	ISBN            string `gorm:"uniqueIndex"`
 	PublicationYear int
 	Genre           string
+	Category        string   `gorm:"default:book"`
+	WorkKey         string   `gorm:"index"`
 	Author          []Author `gorm:"many2many:author_books;"`
 	BookCopy        []BookCopy
 	Reservation     []Reservation
@@ -1,13 +1,20 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type BookCopy struct {
 	gorm.Model
-	Status string
-	BookID uuid.UUID
-	Loan   []Loan
+	Status          string
+	Barcode         *string `gorm:"uniqueIndex"`
+	BookID          uuid.UUID
+	ReplacementCost float64
+	Location        string
+	Condition       string
+	ShelfSearchAt   *time.Time `gorm:"index"`
+	Loan            []Loan
 }
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OpeningHours holds one row per weekday, a weekday without a row is
+// treated as open.
+type OpeningHours struct {
+	Weekday   int `gorm:"primaryKey;autoIncrement:false"`
+	OpensAt   string
+	ClosesAt  string
+	Closed    bool
+	UpdatedAt time.Time
+}
+
+type Closure struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	StartDate time.Time `gorm:"type:date;not null"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	Reason    string
+	UID       *string `gorm:"uniqueIndex"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
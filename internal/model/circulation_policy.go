@@ -0,0 +1,16 @@
+package model
+
+import "gorm.io/gorm"
+
+type CirculationPolicy struct {
+	gorm.Model
+	MemberCategory string `gorm:"uniqueIndex:idx_circulation_policy_dimensions"`
+	ItemCategory   string `gorm:"uniqueIndex:idx_circulation_policy_dimensions"`
+	Genre          string `gorm:"uniqueIndex:idx_circulation_policy_dimensions"`
+	LoanDays       int
+	MaxRenewals    int
+	MaxItems       int
+	DailyFineRate  float64
+	GraceDays      int
+	MaxFine        float64
+}
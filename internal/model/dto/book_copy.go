@@ -1,15 +1,75 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
 
 type BookCopyRequest struct {
-	Copies uint      `json:"copies"`
-	Status string    `json:"status"`
-	BookID uuid.UUID `json:"book_id"`
+	Copies          uint      `json:"copies"`
+	Status          string    `json:"status"`
+	BookID          uuid.UUID `json:"book_id"`
+	ReplacementCost *float64  `json:"replacement_cost"`
+	Barcode         *string   `json:"barcode"`
+	Location        *string   `json:"location"`
+	Condition       *string   `json:"condition"`
+	Reason          string    `json:"reason"`
 }
 
 type BookCopyResponse struct {
-	ID     uint      `json:"id"`
-	Status string    `json:"status"`
-	BookID uuid.UUID `json:"book_id"`
+	ID              uint       `json:"id"`
+	Status          string     `json:"status"`
+	Barcode         string     `json:"barcode,omitempty"`
+	BookID          uuid.UUID  `json:"book_id"`
+	ReplacementCost float64    `json:"replacement_cost"`
+	Location        string     `json:"location,omitempty"`
+	Condition       string     `json:"condition,omitempty"`
+	ShelfSearchAt   *time.Time `json:"shelf_search_at,omitempty"`
+}
+
+type ItemEventResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	BookCopyID uint       `json:"book_copy_id"`
+	LoanID     *uuid.UUID `json:"loan_id,omitempty"`
+	Kind       string     `json:"kind"`
+	FromStatus string     `json:"from_status,omitempty"`
+	ToStatus   string     `json:"to_status,omitempty"`
+	FromValue  string     `json:"from_value,omitempty"`
+	ToValue    string     `json:"to_value,omitempty"`
+	ActorID    *uuid.UUID `json:"actor_id"`
+	KioskID    *uuid.UUID `json:"kiosk_id,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func ToBookCopyResponse(copy model.BookCopy) BookCopyResponse {
+	return BookCopyResponse{
+		ID:              copy.ID,
+		Status:          copy.Status,
+		Barcode:         deref(copy.Barcode),
+		BookID:          copy.BookID,
+		ReplacementCost: copy.ReplacementCost,
+		Location:        copy.Location,
+		Condition:       copy.Condition,
+		ShelfSearchAt:   copy.ShelfSearchAt,
+	}
+}
+
+func ToItemEventResponse(event model.ItemEvent) ItemEventResponse {
+	return ItemEventResponse{
+		ID:         event.ID,
+		BookCopyID: event.BookCopyID,
+		LoanID:     event.LoanID,
+		Kind:       event.Kind,
+		FromStatus: event.FromStatus,
+		ToStatus:   event.ToStatus,
+		FromValue:  event.FromValue,
+		ToValue:    event.ToValue,
+		ActorID:    event.ActorID,
+		KioskID:    event.KioskID,
+		Reason:     event.Reason,
+		CreatedAt:  event.CreatedAt,
+	}
 }
@@ -13,6 +13,8 @@ type BookRequest struct {
 	ISBN            string `json:"isbn"`
 	PublicationYear int    `json:"publication_year"`
 	Genre           string `json:"genre"`
+	Category        string `json:"category"`
+	WorkKey         string `json:"work_key"`
 	InitialCopy     uint   `json:"initial_copy"`
 	AuthorIds       []int  `json:"authors"`
 }
@@ -23,6 +25,8 @@ type BookResponse struct {
 	ISBN            string              `json:"isbn"`
 	PublicationYear int                 `json:"publication_year"`
 	Genre           string              `json:"genre"`
+	Category        string              `json:"category"`
+	WorkKey         string              `json:"work_key,omitempty"`
 	Authors         []map[string]string `json:"authors"`
 }
 
@@ -32,6 +36,8 @@ type BookResponseShort struct {
 	ISBN            string    `json:"isbn"`
 	PublicationYear int       `json:"publication_year"`
 	Genre           string    `json:"genre"`
+	Category        string    `json:"category"`
+	WorkKey         string    `json:"work_key,omitempty"`
 }
 
 func ToBookResponse(book model.Book) BookResponse {
@@ -53,6 +59,8 @@ func ToBookResponse(book model.Book) BookResponse {
 		ISBN:            book.ISBN,
 		PublicationYear: book.PublicationYear,
 		Genre:           book.Genre,
+		Category:        book.Category,
+		WorkKey:         book.WorkKey,
 		Authors:         authorsSlice,
 	}
 }
@@ -79,6 +87,8 @@ func ToBookModel(id uuid.UUID, data BookRequest) model.Book {
 		ISBN:            data.ISBN,
 		PublicationYear: data.PublicationYear,
 		Genre:           data.Genre,
+		Category:        data.Category,
+		WorkKey:         data.WorkKey,
 		Author:          authors,
 	}
 }
@@ -90,5 +100,7 @@ func ToBookResponseShort(book model.Book) BookResponseShort {
 		ISBN:            book.ISBN,
 		PublicationYear: book.PublicationYear,
 		Genre:           book.Genre,
+		Category:        book.Category,
+		WorkKey:         book.WorkKey,
 	}
 }
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type OpeningHoursRequest struct {
+	OpensAt  string `json:"opens_at" validate:"omitempty,datetime=15:04"`
+	ClosesAt string `json:"closes_at" validate:"omitempty,datetime=15:04"`
+	Closed   bool   `json:"closed"`
+}
+
+type OpeningHoursResponse struct {
+	Weekday  int    `json:"weekday"`
+	Day      string `json:"day"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+	Closed   bool   `json:"closed"`
+}
+
+type ClosureRequest struct {
+	StartDate string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" validate:"required,datetime=2006-01-02"`
+	Reason    string `json:"reason"`
+}
+
+type ClosureResponse struct {
+	ID        uuid.UUID `json:"id"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+	Reason    string    `json:"reason"`
+}
+
+type CalendarResponse struct {
+	Hours    []OpeningHoursResponse `json:"hours"`
+	Closures []ClosureResponse      `json:"closures"`
+}
+
+type ClosureImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+func ToOpeningHoursResponse(hours model.OpeningHours) OpeningHoursResponse {
+	return OpeningHoursResponse{
+		Weekday:  hours.Weekday,
+		Day:      time.Weekday(hours.Weekday).String(),
+		OpensAt:  hours.OpensAt,
+		ClosesAt: hours.ClosesAt,
+		Closed:   hours.Closed,
+	}
+}
+
+func ToClosureResponse(closure model.Closure) ClosureResponse {
+	return ClosureResponse{
+		ID:        closure.ID,
+		StartDate: closure.StartDate.UTC().Format("2006-01-02"),
+		EndDate:   closure.EndDate.UTC().Format("2006-01-02"),
+		Reason:    closure.Reason,
+	}
+}
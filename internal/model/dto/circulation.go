@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type PatronSessionRequest struct {
+	MemberBarcode string `json:"member_barcode"`
+}
+
+type PatronSessionResponse struct {
+	ID         uuid.UUID      `json:"id"`
+	Member     MemberResponse `json:"member"`
+	StaffID    *uuid.UUID     `json:"staff_id,omitempty"`
+	KioskID    *uuid.UUID     `json:"kiosk_id,omitempty"`
+	LastScanAt time.Time      `json:"last_scan_at"`
+	EndedAt    *time.Time     `json:"ended_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// KioskSessionRequest signs a member in at a kiosk with their card and PIN.
+type KioskSessionRequest struct {
+	Barcode string `json:"barcode"`
+	PIN     string `json:"pin"`
+}
+
+// ScanRequest is one barcode read at the desk. SessionID is only needed
+// when the item is to be checked out.
+type ScanRequest struct {
+	SessionID      *uuid.UUID `json:"session_id"`
+	Barcode        string     `json:"barcode"`
+	Override       bool       `json:"override"`
+	OverrideReason string     `json:"override_reason"`
+}
+
+type ScanResponse struct {
+	Action  string         `json:"action"`
+	Loan    *LoanResponse  `json:"loan,omitempty"`
+	Receipt *ReturnReceipt `json:"receipt,omitempty"`
+}
+
+func ToPatronSessionResponse(session model.PatronSession, member model.Member) PatronSessionResponse {
+	return PatronSessionResponse{
+		ID:         session.ID,
+		Member:     ToMemberResponse(member),
+		StaffID:    session.StaffID,
+		KioskID:    session.KioskID,
+		LastScanAt: session.LastScanAt,
+		EndedAt:    session.EndedAt,
+		CreatedAt:  session.CreatedAt,
+	}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "github.com/nanoLeinz/librarium/internal/model"
+
+type CirculationPolicyRequest struct {
+	MemberCategory string  `json:"member_category"`
+	ItemCategory   string  `json:"item_category"`
+	Genre          string  `json:"genre"`
+	LoanDays       int     `json:"loan_days" validate:"gte=1"`
+	MaxRenewals    int     `json:"max_renewals" validate:"gte=0"`
+	MaxItems       int     `json:"max_items" validate:"gte=1"`
+	DailyFineRate  float64 `json:"daily_fine_rate" validate:"gte=0"`
+	GraceDays      int     `json:"grace_days" validate:"gte=0"`
+	MaxFine        float64 `json:"max_fine" validate:"gte=0"`
+}
+
+type CirculationPolicyResponse struct {
+	ID             uint    `json:"id"`
+	MemberCategory string  `json:"member_category"`
+	ItemCategory   string  `json:"item_category"`
+	Genre          string  `json:"genre"`
+	LoanDays       int     `json:"loan_days"`
+	MaxRenewals    int     `json:"max_renewals"`
+	MaxItems       int     `json:"max_items"`
+	DailyFineRate  float64 `json:"daily_fine_rate"`
+	GraceDays      int     `json:"grace_days"`
+	MaxFine        float64 `json:"max_fine"`
+}
+
+func ToCirculationPolicyResponse(policy model.CirculationPolicy) CirculationPolicyResponse {
+	return CirculationPolicyResponse{
+		ID:             policy.ID,
+		MemberCategory: policy.MemberCategory,
+		ItemCategory:   policy.ItemCategory,
+		Genre:          policy.Genre,
+		LoanDays:       policy.LoanDays,
+		MaxRenewals:    policy.MaxRenewals,
+		MaxItems:       policy.MaxItems,
+		DailyFineRate:  policy.DailyFineRate,
+		GraceDays:      policy.GraceDays,
+		MaxFine:        policy.MaxFine,
+	}
+}
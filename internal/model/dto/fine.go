@@ -1 +1,79 @@
 package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type FineRequest struct {
+	MemberID uuid.UUID  `json:"member_id" validate:"required"`
+	LoanID   *uuid.UUID `json:"loan_id"`
+	Amount   float64    `json:"amount" validate:"required,gt=0"`
+	Reason   string     `json:"reason" validate:"required"`
+}
+
+type FinePaymentRequest struct {
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+}
+
+type FineWaiveRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type FinePaymentResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Amount     float64   `json:"amount"`
+	ReceivedBy uuid.UUID `json:"received_by"`
+	PaidAt     time.Time `json:"paid_at"`
+}
+
+type FineResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	LoanID      *uuid.UUID            `json:"loan_id"`
+	MemberID    uuid.UUID             `json:"member_id"`
+	Amount      float64               `json:"amount"`
+	AmountPaid  float64               `json:"amount_paid"`
+	Outstanding float64               `json:"outstanding"`
+	Reason      string                `json:"reason"`
+	Status      string                `json:"status"`
+	WaiveReason string                `json:"waive_reason,omitempty"`
+	WaivedAt    *time.Time            `json:"waived_at,omitempty"`
+	Payments    []FinePaymentResponse `json:"payments"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+func ToFineResponse(fine model.Fine) FineResponse {
+
+	payments := []FinePaymentResponse{}
+	for _, v := range fine.Payments {
+		payments = append(payments, FinePaymentResponse{
+			ID:         v.ID,
+			Amount:     v.Amount,
+			ReceivedBy: v.ReceivedBy,
+			PaidAt:     v.PaidAt,
+		})
+	}
+
+	outstanding := fine.Amount - fine.AmountPaid
+	if fine.Status == enum.WaivedFine.String() || outstanding < 0 {
+		outstanding = 0
+	}
+
+	return FineResponse{
+		ID:          fine.ID,
+		LoanID:      fine.LoanID,
+		MemberID:    fine.MemberID,
+		Amount:      fine.Amount,
+		AmountPaid:  fine.AmountPaid,
+		Outstanding: outstanding,
+		Reason:      fine.Reason,
+		Status:      fine.Status,
+		WaiveReason: fine.WaiveReason,
+		WaivedAt:    fine.WaivedAt,
+		Payments:    payments,
+		CreatedAt:   fine.CreatedAt,
+	}
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type KioskRequest struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Location string `json:"location" validate:"omitempty,max=100"`
+}
+
+// KioskResponse carries Key only when the kiosk is registered; it cannot
+// be read back later.
+type KioskResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Location   string     `json:"location,omitempty"`
+	Active     bool       `json:"active"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Key        string     `json:"key,omitempty"`
+}
+
+type PINRequest struct {
+	PIN string `json:"pin" validate:"required,numeric,min=4,max=8"`
+}
+
+func ToKioskResponse(kiosk model.Kiosk) KioskResponse {
+	return KioskResponse{
+		ID:         kiosk.ID,
+		Name:       kiosk.Name,
+		Location:   kiosk.Location,
+		Active:     kiosk.Active,
+		LastSeenAt: kiosk.LastSeenAt,
+		CreatedAt:  kiosk.CreatedAt,
+	}
+}
@@ -8,35 +8,154 @@ import (
 )
 
 type LoanRequest struct {
-	MemberID   uuid.UUID `json:"member_id"`
-	BookCopyID uint      `json:"book_copy_id"`
-	Status     string    `json:"status"`
+	MemberID       uuid.UUID `json:"member_id"`
+	BookCopyID     uint      `json:"book_copy_id"`
+	Status         string    `json:"status"`
+	Override       bool      `json:"override"`
+	OverrideReason string    `json:"override_reason"`
+}
+
+// CheckoutBlock is one reason a member may not borrow. Limit and Actual
+// are item counts or amounts owed, depending on the reason.
+type CheckoutBlock struct {
+	Reason  string  `json:"reason"`
+	Message string  `json:"message"`
+	Limit   float64 `json:"limit"`
+	Actual  float64 `json:"actual"`
 }
 
 type LoanUpdateRequest struct {
-	Status     string `json:"status"`
-	BookCopyID uint   `json:"book_copy_id"`
-	BookStatus string `json:"book_status"`
+	Status string `json:"status"`
+}
+
+// LoanReturnRequest backdates the return to ReturnedAt when it is set,
+// e.g. for items left in the book drop while the library was closed.
+type LoanReturnRequest struct {
+	Damaged       bool       `json:"damaged"`
+	ConditionNote string     `json:"condition_note"`
+	DamageCharge  *float64   `json:"damage_charge"`
+	ReturnedAt    *time.Time `json:"returned_at"`
+}
+
+// BatchReturnRequest checks in copies found together, e.g. in the book
+// drop, by barcode and/or copy ID. ReturnedAt defaults to now; set it to
+// when the drop was last emptied to backdate the returns. Either way a
+// time on a closed day moves back to the last open day before it.
+type BatchReturnRequest struct {
+	Barcodes   []string   `json:"barcodes"`
+	CopyIDs    []uint     `json:"copy_ids"`
+	ReturnedAt *time.Time `json:"returned_at"`
+}
+
+type BatchReturnItem struct {
+	Barcode           string        `json:"barcode,omitempty"`
+	BookCopyID        uint          `json:"book_copy_id,omitempty"`
+	Outcome           string        `json:"outcome"`
+	LoanID            *uuid.UUID    `json:"loan_id,omitempty"`
+	MemberID          *uuid.UUID    `json:"member_id,omitempty"`
+	Fine              *FineResponse `json:"fine,omitempty"`
+	HoldReservationID *uuid.UUID    `json:"hold_reservation_id,omitempty"`
+	Error             string        `json:"error,omitempty"`
+}
+
+// BatchReturnResponse reports every item in request order; Summary counts
+// them by outcome.
+type BatchReturnResponse struct {
+	ReturnedAt time.Time         `json:"returned_at"`
+	Summary    map[string]int    `json:"summary"`
+	Items      []BatchReturnItem `json:"items"`
+}
+
+type ClaimReturnedRequest struct {
+	Note string `json:"note"`
+}
+
+// ClaimResolveRequest settles a claims-returned loan: "found" closes it as
+// returned on the day of the claim, "lost" bills the member for the copy.
+type ClaimResolveRequest struct {
+	Resolution string `json:"resolution"`
+	Note       string `json:"note"`
+}
+
+type ClaimResolveResponse struct {
+	Resolution  string           `json:"resolution"`
+	Receipt     *ReturnReceipt   `json:"receipt,omitempty"`
+	LostReceipt *LostItemReceipt `json:"lost_receipt,omitempty"`
+}
+
+type LostItemReceipt struct {
+	LoanID      uuid.UUID     `json:"loan_id"`
+	MemberID    uuid.UUID     `json:"member_id"`
+	BookCopyID  uint          `json:"book_copy_id"`
+	LoanStatus  string        `json:"loan_status"`
+	CopyStatus  string        `json:"copy_status"`
+	OverdueFine *FineResponse `json:"overdue_fine,omitempty"`
+	LostFine    *FineResponse `json:"lost_fine,omitempty"`
+}
+
+type ReturnReceipt struct {
+	LoanID            uuid.UUID     `json:"loan_id"`
+	MemberID          uuid.UUID     `json:"member_id"`
+	BookCopyID        uint          `json:"book_copy_id"`
+	DueDate           time.Time     `json:"due_date"`
+	ReturnDate        time.Time     `json:"return_date"`
+	DaysOverdue       int           `json:"days_overdue"`
+	Fine              *FineResponse `json:"fine"`
+	DamageFine        *FineResponse `json:"damage_fine,omitempty"`
+	LostFine          *FineResponse `json:"lost_fine,omitempty"`
+	RefundDue         float64       `json:"refund_due,omitempty"`
+	CopyStatus        string        `json:"copy_status"`
+	HoldReservationID *uuid.UUID    `json:"hold_reservation_id"`
 }
 
 type LoanResponse struct {
-	ID         uuid.UUID `json:"id"`
-	MemberID   uuid.UUID `json:"member_id"`
-	BookCopyID uint      `json:"book_copy_id"`
-	LoanDate   time.Time `json:"loan_date"`
-	DueDate    time.Time `json:"due_date"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID           uuid.UUID  `json:"id"`
+	MemberID     uuid.UUID  `json:"member_id"`
+	BookCopyID   uint       `json:"book_copy_id"`
+	LoanDate     time.Time  `json:"loan_date"`
+	DueDate      time.Time  `json:"due_date"`
+	ReturnDate   *time.Time `json:"return_date"`
+	Status       string     `json:"status"`
+	RenewalCount int        `json:"renewal_count"`
+	RecalledAt   *time.Time `json:"recalled_at,omitempty"`
+	ClaimedAt    *time.Time `json:"claimed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type RecallResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	LoanID          uuid.UUID  `json:"loan_id"`
+	ReservationID   uuid.UUID  `json:"reservation_id"`
+	RecalledBy      *uuid.UUID `json:"recalled_by"`
+	PreviousDueDate time.Time  `json:"previous_due_date"`
+	NewDueDate      time.Time  `json:"new_due_date"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 func ToLoanResponse(loan model.Loan) LoanResponse {
 	return LoanResponse{
-		ID:         loan.ID,
-		MemberID:   loan.MemberID,
-		BookCopyID: loan.BookCopyID,
-		LoanDate:   loan.LoanDate,
-		DueDate:    loan.DueDate,
-		Status:     loan.Status,
-		CreatedAt:  loan.CreatedAt,
+		ID:           loan.ID,
+		MemberID:     loan.MemberID,
+		BookCopyID:   loan.BookCopyID,
+		LoanDate:     loan.LoanDate,
+		DueDate:      loan.DueDate,
+		ReturnDate:   loan.ReturnDate,
+		Status:       loan.Status,
+		RenewalCount: loan.RenewalCount,
+		RecalledAt:   loan.RecalledAt,
+		ClaimedAt:    loan.ClaimedAt,
+		CreatedAt:    loan.CreatedAt,
+	}
+}
+
+func ToRecallResponse(recall model.Recall) RecallResponse {
+	return RecallResponse{
+		ID:              recall.ID,
+		LoanID:          recall.LoanID,
+		ReservationID:   recall.ReservationID,
+		RecalledBy:      recall.RecalledBy,
+		PreviousDueDate: recall.PreviousDueDate,
+		NewDueDate:      recall.NewDueDate,
+		CreatedAt:       recall.CreatedAt,
 	}
 }
@@ -11,18 +11,29 @@ import (
 type MemberResponse struct {
 	ID            uuid.UUID `json:"id"`
 	Email         string    `json:"email"`
+	Barcode       string    `json:"barcode,omitempty"`
 	Password      string    `json:"-"`
 	FullName      string    `json:"full_name"`
 	Role          string    `json:"-"`
+	Category      string    `json:"category"`
 	AccountStatus string    `json:"account_status"`
+	ClaimCount    int       `json:"claim_count"`
 	CreatedAt     time.Time `json:"created_at"`
 }
+
+// MemberUpdateRequest is what members may change on their own profile;
+// account status and role are staff decisions made via ManageMember.
 type MemberUpdateRequest struct {
-	ID            uuid.UUID `json:"-"`
-	Email         string    `json:"email" validate:"omitempty,email"`
-	Password      string    `json:"password" validate:"omitempty,min=4"`
-	FullName      string    `json:"full_name" validate:"omitempty,max=50"`
-	AccountStatus string    `json:"account_status" validate:"omitempty"`
+	ID       uuid.UUID `json:"-"`
+	Email    string    `json:"email" validate:"omitempty,email"`
+	Password string    `json:"password" validate:"omitempty,min=4"`
+	FullName string    `json:"full_name" validate:"omitempty,max=50"`
+}
+type MemberManageRequest struct {
+	Role          string `json:"role" validate:"omitempty"`
+	Barcode       string `json:"barcode" validate:"omitempty"`
+	Category      string `json:"category" validate:"omitempty,max=30"`
+	AccountStatus string `json:"account_status" validate:"omitempty"`
 }
 type MemberCreateRequest struct {
 	Email         string `json:"email" validate:"required,email"`
@@ -52,11 +63,22 @@ func ToMemberResponse(member model.Member) MemberResponse {
 	return MemberResponse{
 		ID:            member.ID,
 		Email:         member.Email,
+		Barcode:       deref(member.Barcode),
 		FullName:      member.FullName,
 		Role:          member.Role,
+		Category:      member.Category,
 		AccountStatus: member.AccountStatus,
+		ClaimCount:    member.ClaimCount,
 		CreatedAt:     member.CreatedAt,
 		Password:      member.Password,
 	}
 
 }
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,10 @@
+package dto
+
+type RolePermissionRequest struct {
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+type RolePermissionResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
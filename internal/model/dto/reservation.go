@@ -8,29 +8,45 @@ import (
 )
 
 type ReservationRequest struct {
-	BookID          uuid.UUID `json:"book_id"`
-	MemberID        uuid.UUID `json:"member_id"`
-	ReservationDate time.Time `json:"reservation_date"`
-	Status          string    `json:"status"`
-	QueuePosition   int       `json:"queue"`
+	BookID          uuid.UUID  `json:"book_id"`
+	BookCopyID      *uint      `json:"book_copy_id"`
+	HoldType        string     `json:"hold_type"`
+	MemberID        uuid.UUID  `json:"member_id"`
+	ReservationDate time.Time  `json:"reservation_date"`
+	Status          string     `json:"status"`
+	QueuePosition   int        `json:"queue"`
+	NotNeededAfter  *time.Time `json:"not_needed_after"`
 }
 
 type ReservationResponse struct {
-	ID              uuid.UUID `json:"reservation_id"`
-	BookID          uuid.UUID `json:"book_id"`
-	ReservationDate time.Time `json:"reservation_date"`
-	Status          string    `json:"status"`
-	QueuePosition   int       `json:"queue"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              uuid.UUID  `json:"reservation_id"`
+	BookID          uuid.UUID  `json:"book_id"`
+	MemberID        uuid.UUID  `json:"member_id"`
+	HoldType        string     `json:"hold_type"`
+	ReservationDate time.Time  `json:"reservation_date"`
+	Status          string     `json:"status"`
+	QueuePosition   int        `json:"queue"`
+	BookCopyID      *uint      `json:"book_copy_id,omitempty"`
+	ReadyAt         *time.Time `json:"ready_at,omitempty"`
+	PickupDeadline  *time.Time `json:"pickup_deadline,omitempty"`
+	NotNeededAfter  *time.Time `json:"not_needed_after,omitempty"`
+	EstimatedWait   *int       `json:"estimated_wait_days,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 func ToReservationResponse(s model.Reservation) ReservationResponse {
 	return ReservationResponse{
 		ID:              s.ID,
 		BookID:          s.BookID,
+		MemberID:        s.MemberID,
+		HoldType:        s.HoldType,
 		ReservationDate: s.ReservationDate,
 		Status:          s.Status,
 		QueuePosition:   s.QueuePosition,
+		BookCopyID:      s.BookCopyID,
+		ReadyAt:         s.ReadyAt,
+		PickupDeadline:  s.PickupDeadline,
+		NotNeededAfter:  s.NotNeededAfter,
 		CreatedAt:       s.CreatedAt,
 	}
 }
@@ -8,14 +8,28 @@ import (
 )
 
 type Fine struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	LoanID    uuid.UUID
-	Loan      Loan
-	MemberID  uuid.UUID
-	Amount    float64
-	Reason    string
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	LoanID      *uuid.UUID
+	Loan        *Loan
+	MemberID    uuid.UUID
+	Amount      float64
+	AmountPaid  float64
+	Reason      string
+	Status      string
+	WaiveReason string
+	WaivedBy    *uuid.UUID
+	WaivedAt    *time.Time
+	Payments    []FinePayment
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt
+}
+
+type FinePayment struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	FineID     uuid.UUID
+	Amount     float64
+	ReceivedBy uuid.UUID
+	PaidAt     time.Time
+	CreatedAt  time.Time
 }
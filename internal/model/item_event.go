@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemEvent is one entry in a copy's audit trail. LoanID is set for
+// events raised by circulation, and FromValue/ToValue hold the before and
+// after of whatever field the kind names (location, condition, due date).
+// ActorID is nil for changes made by scheduled jobs, and KioskID is set
+// for self-service actions.
+type ItemEvent struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	BookCopyID uint       `gorm:"index;not null"`
+	LoanID     *uuid.UUID `gorm:"type:uuid;index"`
+	Kind       string     `gorm:"not null"`
+	FromStatus string
+	ToStatus   string
+	FromValue  string
+	ToValue    string
+	ActorID    *uuid.UUID
+	KioskID    *uuid.UUID `gorm:"type:uuid;index"`
+	Reason     string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobRun struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	JobName    string    `gorm:"index;not null"`
+	Instance   string
+	Status     string `gorm:"not null"`
+	Affected   int
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kiosk is a self-service station. It authenticates with its ID and a
+// key shown once at registration; only the key's hash is stored.
+type Kiosk struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name       string    `gorm:"not null"`
+	Location   string
+	KeyHash    string `gorm:"not null"`
+	Active     bool   `gorm:"default:true"`
+	LastSeenAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -8,14 +8,52 @@ import (
 )
 
 type Loan struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	MemberID   uuid.UUID
-	BookCopyID uint
-	LoanDate   time.Time
-	DueDate    time.Time
-	ReturnDate *time.Time
-	Status     string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	DeletedAt  gorm.DeletedAt
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	MemberID     uuid.UUID
+	BookCopyID   uint
+	LoanDate     time.Time
+	DueDate      time.Time
+	ReturnDate   *time.Time
+	Status       string
+	RenewalCount int
+	RecalledAt   *time.Time
+	LostAt       *time.Time
+	ClaimedAt    *time.Time
+	Renewals     []LoanRenewal
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt
+}
+
+type LoanRenewal struct {
+	ID              uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	LoanID          uuid.UUID
+	RenewedBy       uuid.UUID
+	PreviousDueDate time.Time
+	NewDueDate      time.Time
+	CreatedAt       time.Time
+}
+
+// CheckoutOverride records staff forcing a checkout past the blocks in
+// Blocks, a comma separated list of enum.CheckoutBlock values.
+type CheckoutOverride struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	LoanID        uuid.UUID `gorm:"index;not null"`
+	MemberID      uuid.UUID `gorm:"index;not null"`
+	OverriddenBy  uuid.UUID
+	Blocks        string
+	Justification string `gorm:"not null"`
+	CreatedAt     time.Time
+}
+
+// Recall records a loan's due date being pulled forward for a waiting
+// reservation. RecalledBy is nil when the recall job raised it.
+type Recall struct {
+	ID              uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	LoanID          uuid.UUID `gorm:"index;not null"`
+	ReservationID   uuid.UUID `gorm:"index;not null"`
+	RecalledBy      *uuid.UUID
+	PreviousDueDate time.Time
+	NewDueDate      time.Time
+	CreatedAt       time.Time
 }
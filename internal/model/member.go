@@ -10,9 +10,14 @@ import (
 type Member struct {
 	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	Email         string    `gorm:"uniqueIndex"`
+	Barcode       *string   `gorm:"uniqueIndex"`
 	Password      string
+	PIN           string
+	PINFailures   int
+	ClaimCount    int
 	FullName      string
 	Role          string
+	Category      string `gorm:"default:general"`
 	AccountStatus string
 	Loan          []Loan
 	Fine          []Fine
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	MemberID  uuid.UUID `gorm:"index;not null"`
+	Kind      string    `gorm:"not null"`
+	Message   string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
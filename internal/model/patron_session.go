@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PatronSession is opened either by staff scanning a member card at the
+// desk (StaffID set) or by the member signing in at a kiosk (KioskID set).
+// Items scanned while it is open are checked out to MemberID.
+type PatronSession struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	MemberID   uuid.UUID  `gorm:"index;not null"`
+	StaffID    *uuid.UUID `gorm:"index"`
+	KioskID    *uuid.UUID `gorm:"index"`
+	LastScanAt time.Time
+	EndedAt    *time.Time
+	CreatedAt  time.Time
+}
@@ -13,7 +13,13 @@ type Reservation struct {
 	MemberID        uuid.UUID
 	ReservationDate time.Time
 	Status          string
+	HoldType        string `gorm:"default:title"`
+	QueueKey        string `gorm:"index"`
 	QueuePosition   int
+	BookCopyID      *uint
+	ReadyAt         *time.Time
+	PickupDeadline  *time.Time
+	NotNeededAfter  *time.Time
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	DeletedAt       gorm.DeletedAt
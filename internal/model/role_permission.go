@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type RolePermission struct {
+	ID         uint   `gorm:"primaryKey"`
+	Role       string `gorm:"uniqueIndex:idx_role_permission"`
+	Permission string `gorm:"uniqueIndex:idx_role_permission"`
+	CreatedAt  time.Time
+}
+
+// RolePermissionSeed marks a default permission as already granted to a
+// role once, so startup never grants it again after an admin removes it.
+type RolePermissionSeed struct {
+	ID         uint   `gorm:"primaryKey"`
+	Role       string `gorm:"uniqueIndex:idx_role_permission_seed"`
+	Permission string `gorm:"uniqueIndex:idx_role_permission_seed"`
+	CreatedAt  time.Time
+}
@@ -1,6 +1,7 @@
 package myerror
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -9,9 +10,12 @@ import (
 	"github.com/nanoLeinz/librarium/internal/model/dto"
 )
 
+// MyError is an error with the HTTP status it maps to. Details, when set,
+// is returned to the client as the response result.
 type MyError struct {
-	Code   int
-	Status string
+	Code    int
+	Status  string
+	Details any
 }
 
 var (
@@ -32,10 +36,16 @@ func ToWebResponse(err MyError) *dto.WebResponse {
 	return &dto.WebResponse{
 		Code:   err.Code,
 		Status: err.Status,
-		Result: nil,
+		Result: err.Details,
 	}
 }
 
+// WithDetails returns a copy of the error carrying details.
+func (s MyError) WithDetails(details any) MyError {
+	s.Details = details
+	return s
+}
+
 func NewNotFoundError(entity string) MyError {
 	return MyError{
 		Code:   http.StatusNotFound,
@@ -50,6 +60,29 @@ func NewDuplicateError(entity string) MyError {
 	}
 }
 
+func FromError(err error) MyError {
+	var myErr MyError
+	if errors.As(err, &myErr) {
+		return myErr
+	}
+
+	return InternalServerErr
+}
+
+func NewUnauthorizedError(Status string) MyError {
+	return MyError{
+		Code:   http.StatusUnauthorized,
+		Status: Status,
+	}
+}
+
+func NewForbiddenError(Status string) MyError {
+	return MyError{
+		Code:   http.StatusForbidden,
+		Status: Status,
+	}
+}
+
 func NewBadRequestError(Status string) MyError {
 	return MyError{
 		Code:   http.StatusBadRequest,
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"hash/fnv"
+
+	"github.com/nanoLeinz/librarium/internal/helper"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AdvisoryLocker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
+}
+
+type AdvisoryLockerImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewAdvisoryLocker(log *log.Logger, db *gorm.DB) AdvisoryLocker {
+	return &AdvisoryLockerImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *AdvisoryLockerImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// TryLock takes a session level Postgres advisory lock for name without
+// waiting. Session locks belong to a connection, so the lock is taken and
+// released on one pinned connection that is held until unlock is called.
+func (s *AdvisoryLockerImpl) TryLock(ctx context.Context, name string) (func(), bool, error) {
+
+	logger := s.logWithCtx(ctx, "AdvisoryLocker.TryLock").
+		WithField("lock", name)
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		logger.WithError(err).Error("failed getting sql db")
+		return nil, false, err
+	}
+
+	c, err := sqlDB.Conn(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed pinning connection")
+		return nil, false, err
+	}
+
+	key := advisoryKey(name)
+
+	var acquired bool
+	if err := c.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		logger.WithError(err).Error("failed executing query")
+		c.Close()
+		return nil, false, err
+	}
+
+	if !acquired {
+		logger.Debug("lock held elsewhere")
+		c.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		defer c.Close()
+		if _, err := c.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			logger.WithError(err).Error("failed releasing lock")
+			// a connection that still holds the lock must not go back
+			// to the pool
+			c.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}
+
+	logger.Info("lock acquired")
+	return unlock, true, nil
+}
@@ -43,7 +43,7 @@ func (s *AuthorRepositoryImpl) Create(ctx context.Context, author *model.Author)
 
 	logger.Info("executing query")
 
-	result := s.db.Create(author)
+	result := conn(ctx, s.db).Create(author)
 
 	err := result.Error
 	if err != nil {
@@ -66,7 +66,7 @@ func (s *AuthorRepositoryImpl) GetByIDs(ctx context.Context, ids ...uint) (*[]mo
 
 	authors := []model.Author{}
 
-	result := s.db.WithContext(ctx).Preload("Book").Find(&authors, ids)
+	result := conn(ctx, s.db).Preload("Book").Find(&authors, ids)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed executing query")
@@ -89,7 +89,7 @@ func (s *AuthorRepositoryImpl) Update(ctx context.Context, author model.Author)
 
 	logger.Info("executing query")
 
-	result := s.db.WithContext(ctx).Updates(author)
+	result := conn(ctx, s.db).Updates(author)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).
@@ -110,7 +110,7 @@ func (s *AuthorRepositoryImpl) DeleteById(ctx context.Context, id uint) error {
 
 	logger.Info("executing query")
 
-	if err := s.db.WithContext(ctx).Delete(&model.Author{}, id).Error; err != nil {
+	if err := conn(ctx, s.db).Delete(&model.Author{}, id).Error; err != nil {
 		logger.WithError(err).Error("failed executing query")
 		return err
 	}
@@ -127,7 +127,7 @@ func (s *AuthorRepositoryImpl) GetAll(ctx context.Context) (*[]model.Author, err
 
 	authors := []model.Author{}
 
-	if err := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Preload("Book").Find(&authors).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Preload("Book").Find(&authors).Error; err != nil {
 		logger.WithError(err).Error("failed executing query")
 		return nil, err
 	} else if len(authors) == 0 {
@@ -149,7 +149,7 @@ func (s *AuthorRepositoryImpl) GetAuthorsBook(ctx context.Context, author *model
 
 	books := []model.Book{}
 
-	err := s.db.WithContext(ctx).Model(author).Association("Book").Find(&books)
+	err := conn(ctx, s.db).Model(author).Association("Book").Find(&books)
 
 	if err != nil {
 		logger.WithError(err).Error("failed to execute query")
@@ -2,16 +2,24 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nanoLeinz/librarium/internal/model"
 )
 
 type BookCopyRepository interface {
-	Create(ctx context.Context, bookId uuid.UUID, status string, copies int) error
+	Create(ctx context.Context, bookId uuid.UUID, status string, copies int) ([]model.BookCopy, error)
 	Update(ctx context.Context, bookCopy *model.BookCopy) error
 	DeleteById(ctx context.Context, bookCopyId uint) error
 	GetByID(ctx context.Context, bookCopyId uint) (*model.BookCopy, error)
+	GetByIDForUpdate(ctx context.Context, bookCopyId uint) (*model.BookCopy, error)
+	GetByBarcode(ctx context.Context, barcode string) (*model.BookCopy, error)
 	GetAll(ctx context.Context) (*[]model.BookCopy, error)
+	CountByStatus(ctx context.Context, bookID uuid.UUID, statuses ...string) (int64, error)
+	CountByWork(ctx context.Context, workKey string, statuses ...string) (int64, error)
+	GetByBook(ctx context.Context, bookID uuid.UUID) ([]model.BookCopy, error)
 	GetByCondition(ctx context.Context, bookCopy *model.BookCopy) (*[]model.BookCopy, error)
+	SetShelfSearch(ctx context.Context, bookCopyId uint, at *time.Time) error
+	GetShelfSearch(ctx context.Context) ([]model.BookCopy, error)
 }
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/model"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookCopyRepositoryImpl struct {
@@ -35,7 +37,7 @@ func (s *BookCopyRepositoryImpl) logWithCtx(ctx context.Context, function string
 	return logging
 }
 
-func (s *BookCopyRepositoryImpl) Create(ctx context.Context, bookId uuid.UUID, status string, copies int) error {
+func (s *BookCopyRepositoryImpl) Create(ctx context.Context, bookId uuid.UUID, status string, copies int) ([]model.BookCopy, error) {
 
 	logger := s.logWithCtx(ctx, "BookRepository.Create")
 
@@ -47,19 +49,21 @@ func (s *BookCopyRepositoryImpl) Create(ctx context.Context, bookId uuid.UUID, s
 	bookCopies := []model.BookCopy{}
 
 	for i := 0; i < copies; i++ {
+		barcode := helper.NewBarcode(helper.ItemBarcodePrefix())
 		var bookCopy = model.BookCopy{
-			BookID: bookId,
-			Status: status,
+			BookID:  bookId,
+			Status:  status,
+			Barcode: &barcode,
 		}
 
 		bookCopies = append(bookCopies, bookCopy)
 	}
 
-	result := s.db.WithContext(ctx).Create(&bookCopies)
+	result := conn(ctx, s.db).Create(&bookCopies)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed executing book copy query")
-		return result.Error
+		return nil, result.Error
 	} else if result.RowsAffected == 0 {
 		logger.Debug("query executed but 0 data inserted")
 	} else {
@@ -67,7 +71,7 @@ func (s *BookCopyRepositoryImpl) Create(ctx context.Context, bookId uuid.UUID, s
 			"copies inserted": result.RowsAffected,
 		}).Info("successfully executed book copy insert query")
 	}
-	return nil
+	return bookCopies, nil
 }
 
 func (s *BookCopyRepositoryImpl) Update(ctx context.Context, bookCopy *model.BookCopy) error {
@@ -78,7 +82,7 @@ func (s *BookCopyRepositoryImpl) Update(ctx context.Context, bookCopy *model.Boo
 
 	logger.Info("executing update query")
 
-	result := s.db.WithContext(ctx).Updates(bookCopy)
+	result := conn(ctx, s.db).Updates(bookCopy)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed executing update book copy query")
@@ -101,7 +105,7 @@ func (s *BookCopyRepositoryImpl) DeleteById(ctx context.Context, bookCopyId uint
 
 	logger.Info("executing book copy delete query")
 
-	result := s.db.WithContext(ctx).Delete(&model.BookCopy{}, bookCopyId)
+	result := conn(ctx, s.db).Delete(&model.BookCopy{}, bookCopyId)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed to execute delete book copy by id")
@@ -125,7 +129,7 @@ func (s *BookCopyRepositoryImpl) GetByID(ctx context.Context, bookCopyId uint) (
 
 	bookCopy := &model.BookCopy{}
 
-	err := s.db.WithContext(ctx).First(bookCopy, bookCopyId).Error
+	err := conn(ctx, s.db).First(bookCopy, bookCopyId).Error
 	if err != nil {
 		logger.WithError(err).Error("failed executing get by id quert")
 
@@ -137,13 +141,55 @@ func (s *BookCopyRepositoryImpl) GetByID(ctx context.Context, bookCopyId uint) (
 	return bookCopy, nil
 }
 
+func (s *BookCopyRepositoryImpl) GetByBarcode(ctx context.Context, barcode string) (*model.BookCopy, error) {
+
+	logger := s.logWithCtx(ctx, "BookCopyRepository.GetByBarcode").WithFields(log.Fields{
+		"barcode": barcode,
+	})
+
+	logger.Info("executing get by barcode query")
+
+	bookCopy := &model.BookCopy{}
+
+	err := conn(ctx, s.db).Where("barcode = ?", barcode).First(bookCopy).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing get by barcode query")
+		return nil, err
+	}
+
+	logger.WithField("bookCopyID", bookCopy.ID).Info("get by barcode query executed successfully")
+	return bookCopy, nil
+}
+
+func (s *BookCopyRepositoryImpl) GetByIDForUpdate(ctx context.Context, bookCopyId uint) (*model.BookCopy, error) {
+
+	logger := s.logWithCtx(ctx, "BookCopyRepository.GetByIDForUpdate").WithFields(log.Fields{
+		"bookCopyID": bookCopyId,
+	})
+
+	logger.Info("executing get by id for update query")
+
+	bookCopy := &model.BookCopy{}
+
+	err := conn(ctx, s.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(bookCopy, bookCopyId).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing get by id for update query")
+
+		return nil, err
+	}
+
+	logger.Info("get by id for update query executed successfully")
+
+	return bookCopy, nil
+}
+
 func (s *BookCopyRepositoryImpl) GetAll(ctx context.Context) (*[]model.BookCopy, error) {
 
 	s.logWithCtx(ctx, "BookCopyRepository.GetAll").Info("executing get all query")
 
 	var copies []model.BookCopy
 
-	err := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Find(&copies).Error
+	err := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Find(&copies).Error
 
 	if err != nil {
 		s.logWithCtx(ctx, "BookCopyRepository.GetAll").Error("failed executing get all query")
@@ -165,7 +211,7 @@ func (s *BookCopyRepositoryImpl) GetByCondition(ctx context.Context, bookCopy *m
 
 	var copies []model.BookCopy
 
-	err := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Where(bookCopy).Find(&copies).Error
+	err := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Where(bookCopy).Find(&copies).Error
 
 	if err != nil {
 		logger.WithError(err).Error("failed executing get by condition query")
@@ -176,3 +222,123 @@ func (s *BookCopyRepositoryImpl) GetByCondition(ctx context.Context, bookCopy *m
 	return &copies, nil
 
 }
+
+func (s *BookCopyRepositoryImpl) CountByStatus(ctx context.Context, bookID uuid.UUID, statuses ...string) (int64, error) {
+
+	logger := s.logWithCtx(ctx, "BookCopyRepository.CountByStatus").WithFields(log.Fields{
+		"bookID":   bookID,
+		"statuses": statuses,
+	})
+
+	logger.Info("executing count by status query")
+
+	var count int64
+
+	err := conn(ctx, s.db).Model(&model.BookCopy{}).Where("book_id = ? AND status IN ?", bookID, statuses).Count(&count).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing count by status query")
+		return 0, err
+	}
+
+	logger.WithField("count", count).Info("count by status query executed successfully")
+	return count, nil
+}
+
+// CountByWork counts the copies in statuses across every edition sharing
+// workKey.
+func (s *BookCopyRepositoryImpl) CountByWork(ctx context.Context, workKey string, statuses ...string) (int64, error) {
+
+	logger := s.logWithCtx(ctx, "BookCopyRepository.CountByWork").WithFields(log.Fields{
+		"workKey":  workKey,
+		"statuses": statuses,
+	})
+
+	logger.Info("executing count by work query")
+
+	var count int64
+
+	err := conn(ctx, s.db).Model(&model.BookCopy{}).
+		Joins("JOIN books ON books.id = book_copies.book_id AND books.deleted_at IS NULL").
+		Where("books.work_key = ? AND book_copies.status IN ?", workKey, statuses).
+		Count(&count).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing count by work query")
+		return 0, err
+	}
+
+	logger.WithField("count", count).Info("count by work query executed successfully")
+	return count, nil
+}
+
+func (s *BookCopyRepositoryImpl) GetByBook(ctx context.Context, bookID uuid.UUID) ([]model.BookCopy, error) {
+
+	logger := s.logWithCtx(ctx, "BookCopyRepository.GetByBook").WithFields(log.Fields{
+		"bookID": bookID,
+	})
+
+	logger.Info("executing get by book query")
+
+	var copies []model.BookCopy
+
+	err := conn(ctx, s.db).Where("book_id = ?", bookID).Order("id").Find(&copies).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing get by book query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(copies)).Info("get by book query executed successfully")
+	return copies, nil
+}
+
+// SetShelfSearch flags the copy for a shelf search from at, or clears the
+// flag when at is nil.
+func (s *BookCopyRepositoryImpl) SetShelfSearch(ctx context.Context, bookCopyId uint, at *time.Time) error {
+
+	logger := s.logWithCtx(ctx, "BookCopyRepository.SetShelfSearch").WithFields(log.Fields{
+		"bookCopyID": bookCopyId,
+		"at":         at,
+	})
+
+	logger.Info("executing update query")
+
+	result := conn(ctx, s.db).
+		Model(&model.BookCopy{}).
+		Where("id = ?", bookCopyId).
+		Update("shelf_search_at", at)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing set shelf search query")
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("set shelf search query executed successfully")
+	return nil
+}
+
+// GetShelfSearch lists the copies flagged for a shelf search, longest
+// searched for first.
+func (s *BookCopyRepositoryImpl) GetShelfSearch(ctx context.Context) ([]model.BookCopy, error) {
+
+	logger := s.logWithCtx(ctx, "BookCopyRepository.GetShelfSearch")
+
+	logger.Info("executing get shelf search query")
+
+	copies := []model.BookCopy{}
+
+	err := conn(ctx, s.db).
+		Scopes(helper.Paginator(ctx)).
+		Where("shelf_search_at IS NOT NULL").
+		Order("shelf_search_at").
+		Find(&copies).Error
+
+	if err != nil {
+		logger.WithError(err).Error("failed executing get shelf search query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(copies)).Info("get shelf search query executed successfully")
+	return copies, nil
+}
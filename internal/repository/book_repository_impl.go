@@ -37,7 +37,7 @@ func (s *BookRepositoryImpl) Create(ctx context.Context, data *model.Book) (*mod
 
 	s.log.WithField("Book Title", data.Title).Info("Inserting to DB")
 
-	if err := s.db.WithContext(ctx).Create(data).Error; err != nil {
+	if err := conn(ctx, s.db).Create(data).Error; err != nil {
 
 		s.log.WithError(err).Error("Error Inserting Book to DB")
 
@@ -60,7 +60,7 @@ func (s *BookRepositoryImpl) Update(ctx context.Context, data *model.Book) error
 		"data":     data,
 	}).Info("Updating to DB")
 
-	if err := s.db.WithContext(ctx).Model(&model.Book{}).Where("id = ?", data.ID).Updates(data).Error; err != nil {
+	if err := conn(ctx, s.db).Model(&model.Book{}).Where("id = ?", data.ID).Updates(data).Error; err != nil {
 
 		s.log.WithError(err).Error("failed to update record")
 
@@ -80,7 +80,7 @@ func (s *BookRepositoryImpl) DeleteByID(ctx context.Context, id uuid.UUID) error
 		"Book ID":  id.String(),
 	}).Info("deleting record DB")
 
-	if err := s.db.WithContext(ctx).Delete(&model.Book{}, id).Error; err != nil {
+	if err := conn(ctx, s.db).Delete(&model.Book{}, id).Error; err != nil {
 
 		s.log.WithError(err).Error("failed deleting record")
 		return err
@@ -103,7 +103,7 @@ func (s *BookRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.
 
 	var data = &model.Book{}
 
-	if err := s.db.WithContext(ctx).First(data, id).Error; err != nil {
+	if err := conn(ctx, s.db).First(data, id).Error; err != nil {
 		s.log.WithError(err).Error("failed fetching record")
 		return nil, err
 
@@ -125,7 +125,7 @@ func (s *BookRepositoryImpl) GetByTitle(ctx context.Context, name string) (*[]mo
 
 	var datas = &[]model.Book{}
 
-	if err := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Where("title LIKE ?", "%"+name+"%").Preload("Author").Find(datas).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Where("title LIKE ?", "%"+name+"%").Preload("Author").Find(datas).Error; err != nil {
 		s.log.WithError(err).Error("failed fetching record")
 		return nil, err
 	}
@@ -144,7 +144,7 @@ func (s *BookRepositoryImpl) GetAll(ctx context.Context) (*[]model.Book, error)
 
 	var datas = &[]model.Book{}
 
-	if err := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Preload("Author").Find(datas).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Preload("Author").Find(datas).Error; err != nil {
 		s.log.WithError(err).Error("failed fetching record")
 
 		return nil, err
@@ -167,7 +167,7 @@ func (s *BookRepositoryImpl) GetBooksAuthor(ctx context.Context, book *model.Boo
 
 	var authors = []model.Author{}
 
-	err := s.db.WithContext(ctx).Model(book).Association("Author").Find(&authors)
+	err := conn(ctx, s.db).Model(book).Association("Author").Find(&authors)
 	if err != nil {
 		logger.WithError(err).Error("failed to execute query")
 		return nil, err
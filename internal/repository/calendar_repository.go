@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type CalendarRepository interface {
+	GetOpeningHours(ctx context.Context) ([]model.OpeningHours, error)
+	SaveOpeningHours(ctx context.Context, hours *model.OpeningHours) error
+	GetClosures(ctx context.Context) ([]model.Closure, error)
+	CreateClosure(ctx context.Context, closure *model.Closure) error
+	UpsertClosure(ctx context.Context, closure *model.Closure) error
+	DeleteClosure(ctx context.Context, id uuid.UUID) error
+}
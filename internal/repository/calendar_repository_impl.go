@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewCalendarRepository(log *log.Logger, db *gorm.DB) CalendarRepository {
+	return &CalendarRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *CalendarRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *CalendarRepositoryImpl) GetOpeningHours(ctx context.Context) ([]model.OpeningHours, error) {
+
+	logger := s.logWithCtx(ctx, "CalendarRepository.GetOpeningHours")
+
+	logger.Info("executing query")
+
+	hours := []model.OpeningHours{}
+
+	if err := conn(ctx, s.db).Order("weekday").Find(&hours).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(hours)).Info("query executed successfully")
+	return hours, nil
+}
+
+func (s *CalendarRepositoryImpl) SaveOpeningHours(ctx context.Context, hours *model.OpeningHours) error {
+
+	logger := s.logWithCtx(ctx, "CalendarRepository.SaveOpeningHours").
+		WithField("weekday", hours.Weekday)
+
+	logger.Info("executing query")
+
+	err := conn(ctx, s.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "weekday"}},
+			DoUpdates: clause.AssignmentColumns([]string{"opens_at", "closes_at", "closed", "updated_at"}),
+		}).
+		Create(hours).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+func (s *CalendarRepositoryImpl) GetClosures(ctx context.Context) ([]model.Closure, error) {
+
+	logger := s.logWithCtx(ctx, "CalendarRepository.GetClosures")
+
+	logger.Info("executing query")
+
+	closures := []model.Closure{}
+
+	if err := conn(ctx, s.db).Order("start_date").Find(&closures).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(closures)).Info("query executed successfully")
+	return closures, nil
+}
+
+func (s *CalendarRepositoryImpl) CreateClosure(ctx context.Context, closure *model.Closure) error {
+
+	logger := s.logWithCtx(ctx, "CalendarRepository.CreateClosure").
+		WithFields(log.Fields{
+			"startDate": closure.StartDate,
+			"endDate":   closure.EndDate,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(closure).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("closureID", closure.ID).Info("query executed successfully")
+	return nil
+}
+
+// UpsertClosure keys on the iCalendar UID so importing the same feed twice
+// updates the existing closures instead of duplicating them.
+func (s *CalendarRepositoryImpl) UpsertClosure(ctx context.Context, closure *model.Closure) error {
+
+	logger := s.logWithCtx(ctx, "CalendarRepository.UpsertClosure").
+		WithFields(log.Fields{
+			"startDate": closure.StartDate,
+			"endDate":   closure.EndDate,
+		})
+
+	logger.Info("executing query")
+
+	err := conn(ctx, s.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "uid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"start_date", "end_date", "reason", "updated_at"}),
+		}).
+		Create(closure).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+func (s *CalendarRepositoryImpl) DeleteClosure(ctx context.Context, id uuid.UUID) error {
+
+	logger := s.logWithCtx(ctx, "CalendarRepository.DeleteClosure").
+		WithField("closureID", id)
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).Delete(&model.Closure{}, id)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type CirculationPolicyRepository interface {
+	Create(ctx context.Context, policy *model.CirculationPolicy) (*model.CirculationPolicy, error)
+	Update(ctx context.Context, policy *model.CirculationPolicy) error
+	DeleteByID(ctx context.Context, id uint) error
+	GetAll(ctx context.Context) ([]model.CirculationPolicy, error)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CirculationPolicyRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewCirculationPolicyRepository(log *log.Logger, db *gorm.DB) CirculationPolicyRepository {
+	return &CirculationPolicyRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *CirculationPolicyRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *CirculationPolicyRepositoryImpl) Create(ctx context.Context, policy *model.CirculationPolicy) (*model.CirculationPolicy, error) {
+
+	logger := s.logWithCtx(ctx, "CirculationPolicyRepository.Create").
+		WithFields(log.Fields{
+			"memberCategory": policy.MemberCategory,
+			"itemCategory":   policy.ItemCategory,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(policy).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("policyID", policy.ID).Info("query executed successfully")
+	return policy, nil
+}
+
+func (s *CirculationPolicyRepositoryImpl) Update(ctx context.Context, policy *model.CirculationPolicy) error {
+
+	logger := s.logWithCtx(ctx, "CirculationPolicyRepository.Update").
+		WithField("policyID", policy.ID)
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).
+		Model(policy).
+		Select("MemberCategory", "ItemCategory", "Genre", "LoanDays", "MaxRenewals", "MaxItems", "DailyFineRate", "GraceDays", "MaxFine").
+		Updates(policy)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+func (s *CirculationPolicyRepositoryImpl) DeleteByID(ctx context.Context, id uint) error {
+
+	logger := s.logWithCtx(ctx, "CirculationPolicyRepository.DeleteByID").
+		WithField("policyID", id)
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).Unscoped().Delete(&model.CirculationPolicy{}, id)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+func (s *CirculationPolicyRepositoryImpl) GetAll(ctx context.Context) ([]model.CirculationPolicy, error) {
+
+	logger := s.logWithCtx(ctx, "CirculationPolicyRepository.GetAll")
+
+	logger.Info("executing query")
+
+	policies := []model.CirculationPolicy{}
+
+	if err := conn(ctx, s.db).Order("member_category, item_category").Find(&policies).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(policies)).Info("query executed successfully")
+	return policies, nil
+}
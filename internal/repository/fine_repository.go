@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+)
+
+type FineRepository interface {
+	logWithCtx(ctx context.Context, function string) *log.Entry
+	Create(ctx context.Context, fine *model.Fine) (*model.Fine, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Fine, error)
+	GetByMember(ctx context.Context, memberID uuid.UUID) ([]model.Fine, error)
+	GetAll(ctx context.Context, status string) ([]model.Fine, error)
+	AddPayment(ctx context.Context, payment *model.FinePayment) error
+	Waive(ctx context.Context, fine *model.Fine) error
+	GetByLoanAndReason(ctx context.Context, loanID uuid.UUID, reason string) (*model.Fine, error)
+	UpdateAmount(ctx context.Context, id uuid.UUID, amount float64) error
+	SumOutstandingByMember(ctx context.Context, memberID uuid.UUID) (float64, error)
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type FineRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewFineRepository(log *log.Logger, db *gorm.DB) FineRepository {
+	return &FineRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *FineRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *FineRepositoryImpl) Create(ctx context.Context, fine *model.Fine) (*model.Fine, error) {
+
+	logger := s.logWithCtx(ctx, "FineRepository.Create").
+		WithFields(log.Fields{
+			"memberID": fine.MemberID,
+			"loanID":   fine.LoanID,
+			"amount":   fine.Amount,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(fine).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("fineID", fine.ID).Info("query executed successfully")
+	return fine, nil
+}
+
+func (s *FineRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.Fine, error) {
+
+	logger := s.logWithCtx(ctx, "FineRepository.GetByID").
+		WithField("fineID", id)
+
+	logger.Info("executing query")
+
+	fine := model.Fine{}
+
+	err := conn(ctx, s.db).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB { return db.Order("paid_at") }).
+		First(&fine, id).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.Info("query executed successfully")
+	return &fine, nil
+}
+
+func (s *FineRepositoryImpl) GetByMember(ctx context.Context, memberID uuid.UUID) ([]model.Fine, error) {
+
+	logger := s.logWithCtx(ctx, "FineRepository.GetByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("executing query")
+
+	fines := []model.Fine{}
+
+	err := conn(ctx, s.db).
+		Scopes(helper.Paginator(ctx)).
+		Preload("Payments").
+		Where("member_id = ?", memberID).
+		Order("created_at desc").
+		Find(&fines).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(fines)).Info("query executed successfully")
+	return fines, nil
+}
+
+func (s *FineRepositoryImpl) GetAll(ctx context.Context, status string) ([]model.Fine, error) {
+
+	logger := s.logWithCtx(ctx, "FineRepository.GetAll").
+		WithField("status", status)
+
+	logger.Info("executing query")
+
+	fines := []model.Fine{}
+
+	q := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Preload("Payments")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	if err := q.Order("created_at desc").Find(&fines).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(fines)).Info("query executed successfully")
+	return fines, nil
+}
+
+func (s *FineRepositoryImpl) AddPayment(ctx context.Context, payment *model.FinePayment) error {
+
+	logger := s.logWithCtx(ctx, "FineRepository.AddPayment").
+		WithFields(log.Fields{
+			"fineID": payment.FineID,
+			"amount": payment.Amount,
+		})
+
+	logger.Info("executing query")
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Exec(`UPDATE fines
+			SET amount_paid = amount_paid + ?,
+				status = CASE WHEN amount_paid + ? >= amount - 0.005 THEN ? ELSE ? END,
+				updated_at = ?
+			WHERE id = ?
+			AND status IN (?, ?)
+			AND amount - amount_paid >= ? - 0.005
+			AND deleted_at IS NULL`,
+			payment.Amount,
+			payment.Amount,
+			enum.PaidFine.String(),
+			enum.PartiallyPaidFine.String(),
+			time.Now().Local(),
+			payment.FineID,
+			enum.UnpaidFine.String(),
+			enum.PartiallyPaidFine.String(),
+			payment.Amount,
+		)
+
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(payment).Error
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("paymentID", payment.ID).Info("query executed successfully")
+	return nil
+}
+
+func (s *FineRepositoryImpl) Waive(ctx context.Context, fine *model.Fine) error {
+
+	logger := s.logWithCtx(ctx, "FineRepository.Waive").
+		WithFields(log.Fields{
+			"fineID":   fine.ID,
+			"waivedBy": fine.WaivedBy,
+		})
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).
+		Model(&model.Fine{}).
+		Where("id = ? AND status IN (?, ?)", fine.ID, enum.UnpaidFine.String(), enum.PartiallyPaidFine.String()).
+		Updates(map[string]interface{}{
+			"status":       enum.WaivedFine.String(),
+			"waive_reason": fine.WaiveReason,
+			"waived_by":    fine.WaivedBy,
+			"waived_at":    fine.WaivedAt,
+		})
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+func (s *FineRepositoryImpl) GetByLoanAndReason(ctx context.Context, loanID uuid.UUID, reason string) (*model.Fine, error) {
+
+	logger := s.logWithCtx(ctx, "FineRepository.GetByLoanAndReason").
+		WithFields(log.Fields{
+			"loanID": loanID,
+			"reason": reason,
+		})
+
+	logger.Info("executing query")
+
+	fine := model.Fine{}
+
+	err := conn(ctx, s.db).Where("loan_id = ? AND reason = ?", loanID, reason).First(&fine).Error
+	if err != nil {
+		logger.WithError(err).Debug("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("fineID", fine.ID).Info("query executed successfully")
+	return &fine, nil
+}
+
+func (s *FineRepositoryImpl) UpdateAmount(ctx context.Context, id uuid.UUID, amount float64) error {
+
+	logger := s.logWithCtx(ctx, "FineRepository.UpdateAmount").
+		WithFields(log.Fields{
+			"fineID": id,
+			"amount": amount,
+		})
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).
+		Exec(`UPDATE fines
+			SET amount = ?,
+				status = CASE
+					WHEN amount_paid >= ? - 0.005 THEN ?
+					WHEN amount_paid > 0 THEN ?
+					ELSE ? END,
+				updated_at = ?
+			WHERE id = ?
+			AND status <> ?
+			AND deleted_at IS NULL`,
+			amount,
+			amount,
+			enum.PaidFine.String(),
+			enum.PartiallyPaidFine.String(),
+			enum.UnpaidFine.String(),
+			time.Now().Local(),
+			id,
+			enum.WaivedFine.String(),
+		)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+	} else {
+		logger.Info("query executed successfully")
+	}
+
+	return nil
+}
+
+// SumOutstandingByMember totals what the member still owes on unpaid and
+// partially paid fines.
+func (s *FineRepositoryImpl) SumOutstandingByMember(ctx context.Context, memberID uuid.UUID) (float64, error) {
+
+	logger := s.logWithCtx(ctx, "FineRepository.SumOutstandingByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("executing query")
+
+	var total float64
+
+	err := conn(ctx, s.db).
+		Model(&model.Fine{}).
+		Select("COALESCE(SUM(amount - amount_paid), 0)").
+		Where("member_id = ? AND status IN (?, ?)", memberID, enum.UnpaidFine.String(), enum.PartiallyPaidFine.String()).
+		Scan(&total).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return 0, err
+	}
+
+	logger.WithField("total", total).Info("query executed successfully")
+	return total, nil
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type ItemEventRepository interface {
+	Create(ctx context.Context, event *model.ItemEvent) error
+	GetByCopy(ctx context.Context, copyID uint) ([]model.ItemEvent, error)
+}
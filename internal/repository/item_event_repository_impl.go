@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ItemEventRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewItemEventRepository(log *log.Logger, db *gorm.DB) ItemEventRepository {
+	return &ItemEventRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *ItemEventRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *ItemEventRepositoryImpl) Create(ctx context.Context, event *model.ItemEvent) error {
+
+	logger := s.logWithCtx(ctx, "ItemEventRepository.Create").
+		WithFields(log.Fields{
+			"bookCopyID": event.BookCopyID,
+			"kind":       event.Kind,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(event).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("eventID", event.ID).Info("query executed successfully")
+	return nil
+}
+
+func (s *ItemEventRepositoryImpl) GetByCopy(ctx context.Context, copyID uint) ([]model.ItemEvent, error) {
+
+	logger := s.logWithCtx(ctx, "ItemEventRepository.GetByCopy").
+		WithField("bookCopyID", copyID)
+
+	logger.Info("executing query")
+
+	events := []model.ItemEvent{}
+
+	err := conn(ctx, s.db).
+		Scopes(helper.Paginator(ctx)).
+		Where("book_copy_id = ?", copyID).
+		Order("created_at desc").
+		Find(&events).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(events)).Info("query executed successfully")
+	return events, nil
+}
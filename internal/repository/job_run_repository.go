@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type JobRunRepository interface {
+	Create(ctx context.Context, run *model.JobRun) error
+	LastStartedAt(ctx context.Context, jobName string) (time.Time, error)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type JobRunRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewJobRunRepository(log *log.Logger, db *gorm.DB) JobRunRepository {
+	return &JobRunRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *JobRunRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *JobRunRepositoryImpl) Create(ctx context.Context, run *model.JobRun) error {
+
+	logger := s.logWithCtx(ctx, "JobRunRepository.Create").
+		WithFields(log.Fields{
+			"job":    run.JobName,
+			"status": run.Status,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(run).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("runID", run.ID).Info("query executed successfully")
+	return nil
+}
+
+// LastStartedAt returns when the latest recorded run of jobName started, or
+// the zero time when the job has never run.
+func (s *JobRunRepositoryImpl) LastStartedAt(ctx context.Context, jobName string) (time.Time, error) {
+
+	logger := s.logWithCtx(ctx, "JobRunRepository.LastStartedAt").
+		WithField("job", jobName)
+
+	logger.Info("executing query")
+
+	var runs []model.JobRun
+	err := conn(ctx, s.db).
+		Where("job_name = ?", jobName).
+		Order("started_at DESC").
+		Limit(1).
+		Find(&runs).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return time.Time{}, err
+	}
+
+	if len(runs) == 0 {
+		logger.Info("job has no recorded runs")
+		return time.Time{}, nil
+	}
+
+	logger.Info("query executed successfully")
+	return runs[0].StartedAt, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type KioskRepository interface {
+	Create(ctx context.Context, kiosk *model.Kiosk) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Kiosk, error)
+	GetAll(ctx context.Context) ([]model.Kiosk, error)
+	Deactivate(ctx context.Context, id uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID, at time.Time) error
+}
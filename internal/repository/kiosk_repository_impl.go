@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type KioskRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewKioskRepository(log *log.Logger, db *gorm.DB) KioskRepository {
+	return &KioskRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *KioskRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *KioskRepositoryImpl) Create(ctx context.Context, kiosk *model.Kiosk) error {
+
+	logger := s.logWithCtx(ctx, "KioskRepository.Create").
+		WithField("name", kiosk.Name)
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(kiosk).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("kioskID", kiosk.ID).Info("query executed successfully")
+	return nil
+}
+
+func (s *KioskRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.Kiosk, error) {
+
+	logger := s.logWithCtx(ctx, "KioskRepository.GetByID").
+		WithField("kioskID", id)
+
+	logger.Info("executing query")
+
+	kiosk := model.Kiosk{}
+
+	if err := conn(ctx, s.db).First(&kiosk, "id = ?", id).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.Info("query executed successfully")
+	return &kiosk, nil
+}
+
+func (s *KioskRepositoryImpl) GetAll(ctx context.Context) ([]model.Kiosk, error) {
+
+	logger := s.logWithCtx(ctx, "KioskRepository.GetAll")
+
+	logger.Info("executing query")
+
+	kiosks := []model.Kiosk{}
+
+	if err := conn(ctx, s.db).Order("created_at").Find(&kiosks).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(kiosks)).Info("query executed successfully")
+	return kiosks, nil
+}
+
+func (s *KioskRepositoryImpl) Deactivate(ctx context.Context, id uuid.UUID) error {
+
+	logger := s.logWithCtx(ctx, "KioskRepository.Deactivate").
+		WithField("kioskID", id)
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).
+		Model(&model.Kiosk{}).
+		Where("id = ?", id).
+		Update("active", false)
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+func (s *KioskRepositoryImpl) Touch(ctx context.Context, id uuid.UUID, at time.Time) error {
+
+	logger := s.logWithCtx(ctx, "KioskRepository.Touch").
+		WithField("kioskID", id)
+
+	logger.Info("executing query")
+
+	err := conn(ctx, s.db).
+		Model(&model.Kiosk{}).
+		Where("id = ?", id).
+		Update("last_seen_at", at).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nanoLeinz/librarium/internal/model"
@@ -14,5 +15,17 @@ type LoanRepository interface {
 	Update(ctx context.Context, loan *model.Loan) error
 	DeleteByID(ctx context.Context, loanID uuid.UUID) error
 	GetByID(ctx context.Context, loanIDs uuid.UUID) (*model.Loan, error)
+	GetByIDForUpdate(ctx context.Context, loanID uuid.UUID) (*model.Loan, error)
+	GetOpenByCopy(ctx context.Context, copyID uint) (*model.Loan, error)
 	GetAll(ctx context.Context) (*[]model.Loan, error)
+	CreateRenewal(ctx context.Context, renewal *model.LoanRenewal) error
+	CreateRecall(ctx context.Context, recall *model.Recall) error
+	CreateOverride(ctx context.Context, override *model.CheckoutOverride) error
+	GetRecallable(ctx context.Context, queueKey string, dueAfter time.Time) (*model.Loan, error)
+	GetByMember(ctx context.Context, memberID uuid.UUID, returned bool) ([]model.Loan, error)
+	AverageLoanDays(ctx context.Context, bookID uuid.UUID) (float64, error)
+	CountOpenByMember(ctx context.Context, memberID uuid.UUID) (int64, error)
+	CountOverdueByMember(ctx context.Context, memberID uuid.UUID, asOf time.Time) (int64, error)
+	MarkOverdue(ctx context.Context, asOf time.Time) ([]model.Loan, error)
+	GetUnreturnedPastDue(ctx context.Context, asOf time.Time) ([]model.Loan, error)
 }
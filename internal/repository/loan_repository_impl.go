@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/model"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LoanRepositoryImpl struct {
@@ -42,7 +45,7 @@ func (s *LoanRepositoryImpl) Create(ctx context.Context, loan *model.Loan) (*mod
 
 	logger.Info("executing query")
 
-	result := s.db.WithContext(ctx).Create(loan)
+	result := conn(ctx, s.db).Create(loan)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed executing query")
@@ -63,7 +66,7 @@ func (s *LoanRepositoryImpl) Update(ctx context.Context, loan *model.Loan) error
 
 	logger.Info("executing query")
 
-	result := s.db.WithContext(ctx).Updates(loan)
+	result := conn(ctx, s.db).Updates(loan)
 
 	if err := result.Error; err != nil {
 		logger.WithError(err).Error("failed executing query")
@@ -84,7 +87,7 @@ func (s *LoanRepositoryImpl) DeleteByID(ctx context.Context, loanID uuid.UUID) e
 
 	logger.Info("executing query")
 
-	result := s.db.WithContext(ctx).Delete(&model.Loan{}, loanID)
+	result := conn(ctx, s.db).Delete(&model.Loan{}, loanID)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed executing query")
@@ -107,7 +110,7 @@ func (s *LoanRepositoryImpl) GetByID(ctx context.Context, loanID uuid.UUID) (*mo
 	logger.Info("executing query")
 
 	var loans = model.Loan{}
-	q := s.db.WithContext(ctx).Find(&loans, loanID)
+	q := conn(ctx, s.db).Find(&loans, loanID)
 	if err := q.Error; err != nil {
 		logger.WithError(err).Error("failed executing query")
 		return nil, err
@@ -120,12 +123,32 @@ func (s *LoanRepositoryImpl) GetByID(ctx context.Context, loanID uuid.UUID) (*mo
 	return &loans, nil
 
 }
+func (s *LoanRepositoryImpl) GetByIDForUpdate(ctx context.Context, loanID uuid.UUID) (*model.Loan, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.GetByIDForUpdate").
+		WithFields(log.Fields{
+			"loanID": loanID,
+		})
+
+	logger.Info("executing query")
+
+	var loan = model.Loan{}
+	err := conn(ctx, s.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, loanID).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.Info("query executed successfully")
+	return &loan, nil
+}
+
 func (s *LoanRepositoryImpl) GetAll(ctx context.Context) (*[]model.Loan, error) {
 	s.logWithCtx(ctx, "LoanRepository.GetAll").Info("executing query")
 
 	var loans = []model.Loan{}
 
-	if err := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Find(&loans).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Find(&loans).Error; err != nil {
 		s.logWithCtx(ctx, "LoanRepository.GetAll").
 			WithError(err).
 			Error("failed executing query")
@@ -144,3 +167,271 @@ func (s *LoanRepositoryImpl) GetAll(ctx context.Context) (*[]model.Loan, error)
 	return &loans, nil
 
 }
+
+// GetByMember lists a member's open loans, or their returned ones when
+// returned is set.
+func (s *LoanRepositoryImpl) GetByMember(ctx context.Context, memberID uuid.UUID, returned bool) ([]model.Loan, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.GetByMember").
+		WithFields(log.Fields{
+			"memberID": memberID,
+			"returned": returned,
+		})
+
+	logger.Info("executing query")
+
+	loans := []model.Loan{}
+
+	query := conn(ctx, s.db).
+		Scopes(helper.Paginator(ctx)).
+		Where("member_id = ?", memberID)
+
+	if returned {
+		query = query.Where("return_date IS NOT NULL").Order("return_date desc")
+	} else {
+		query = query.Where("return_date IS NULL").Order("due_date")
+	}
+
+	if err := query.Find(&loans).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(loans)).Info("query executed successfully")
+	return loans, nil
+}
+
+// AverageLoanDays is the mean length in days of the returned loans of a
+// title, 0 when it has none.
+func (s *LoanRepositoryImpl) AverageLoanDays(ctx context.Context, bookID uuid.UUID) (float64, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.AverageLoanDays").
+		WithField("bookID", bookID)
+
+	logger.Info("executing query")
+
+	var days float64
+
+	err := conn(ctx, s.db).
+		Raw(`SELECT COALESCE(AVG(EXTRACT(EPOCH FROM (l.return_date - l.loan_date)) / 86400), 0)
+		FROM loans AS l
+		JOIN book_copies AS c ON c.id = l.book_copy_id
+		WHERE c.book_id = ? AND l.return_date IS NOT NULL AND l.deleted_at IS NULL`, bookID).
+		Scan(&days).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return 0, err
+	}
+
+	logger.WithField("days", days).Info("query executed successfully")
+	return days, nil
+}
+
+func (s *LoanRepositoryImpl) CountOpenByMember(ctx context.Context, memberID uuid.UUID) (int64, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.CountOpenByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("executing query")
+
+	var count int64
+
+	err := conn(ctx, s.db).
+		Model(&model.Loan{}).
+		Where("member_id = ? AND return_date IS NULL AND status IN (?, ?)", memberID, enum.ActiveLoan.String(), enum.OverdueLoan.String()).
+		Count(&count).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return 0, err
+	}
+
+	logger.WithField("count", count).Info("query executed successfully")
+	return count, nil
+}
+
+// CountOverdueByMember counts the member's open loans past due as of asOf,
+// whether or not the overdue job has flagged them yet.
+func (s *LoanRepositoryImpl) CountOverdueByMember(ctx context.Context, memberID uuid.UUID, asOf time.Time) (int64, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.CountOverdueByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("executing query")
+
+	var count int64
+
+	err := conn(ctx, s.db).
+		Model(&model.Loan{}).
+		Where("member_id = ? AND return_date IS NULL", memberID).
+		Where("status = ? OR (status = ? AND due_date < ?)", enum.OverdueLoan.String(), enum.ActiveLoan.String(), asOf).
+		Count(&count).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return 0, err
+	}
+
+	logger.WithField("count", count).Info("query executed successfully")
+	return count, nil
+}
+
+// MarkOverdue flags active loans past due as overdue and returns the loans
+// it changed.
+func (s *LoanRepositoryImpl) MarkOverdue(ctx context.Context, asOf time.Time) ([]model.Loan, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.MarkOverdue").
+		WithField("asOf", asOf)
+
+	logger.Info("executing query")
+
+	loans := []model.Loan{}
+
+	result := conn(ctx, s.db).
+		Model(&loans).
+		Clauses(clause.Returning{}).
+		Where("status = ? AND return_date IS NULL AND due_date < ?", enum.ActiveLoan.String(), asOf).
+		Update("status", enum.OverdueLoan.String())
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return nil, result.Error
+	}
+
+	logger.WithField("rowsAffected", result.RowsAffected).Info("query executed successfully")
+	return loans, nil
+}
+
+func (s *LoanRepositoryImpl) GetUnreturnedPastDue(ctx context.Context, asOf time.Time) ([]model.Loan, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.GetUnreturnedPastDue").
+		WithField("asOf", asOf)
+
+	logger.Info("executing query")
+
+	loans := []model.Loan{}
+
+	err := conn(ctx, s.db).
+		Where("return_date IS NULL AND due_date < ? AND status IN (?, ?)", asOf, enum.ActiveLoan.String(), enum.OverdueLoan.String()).
+		Find(&loans).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(loans)).Info("query executed successfully")
+	return loans, nil
+}
+
+func (s *LoanRepositoryImpl) CreateRenewal(ctx context.Context, renewal *model.LoanRenewal) error {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.CreateRenewal").
+		WithFields(log.Fields{
+			"loanID":     renewal.LoanID,
+			"newDueDate": renewal.NewDueDate,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(renewal).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("renewalID", renewal.ID).Info("query executed successfully")
+	return nil
+}
+
+func (s *LoanRepositoryImpl) CreateRecall(ctx context.Context, recall *model.Recall) error {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.CreateRecall").
+		WithFields(log.Fields{
+			"loanID":        recall.LoanID,
+			"reservationID": recall.ReservationID,
+			"newDueDate":    recall.NewDueDate,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(recall).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("recallID", recall.ID).Info("query executed successfully")
+	return nil
+}
+
+func (s *LoanRepositoryImpl) CreateOverride(ctx context.Context, override *model.CheckoutOverride) error {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.CreateOverride").
+		WithFields(log.Fields{
+			"loanID": override.LoanID,
+			"blocks": override.Blocks,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(override).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("overrideID", override.ID).Info("query executed successfully")
+	return nil
+}
+
+// GetRecallable locks the longest-outstanding active loan that could fill
+// a reservation in queueKey, has not been recalled yet, and is due after
+// dueAfter. Loans locked by another transaction are skipped. The key
+// expressions mirror helper.CopyQueueKeys.
+func (s *LoanRepositoryImpl) GetRecallable(ctx context.Context, queueKey string, dueAfter time.Time) (*model.Loan, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.GetRecallable").
+		WithFields(log.Fields{
+			"queueKey": queueKey,
+			"dueAfter": dueAfter,
+		})
+
+	logger.Info("executing query")
+
+	loan := &model.Loan{}
+
+	err := conn(ctx, s.db).
+		Select("loans.*").
+		Joins("JOIN book_copies ON book_copies.id = loans.book_copy_id").
+		Joins("JOIN books ON books.id = book_copies.book_id").
+		Where("? IN ('copy:' || book_copies.id, 'title:' || book_copies.book_id, 'work:' || books.work_key)", queueKey).
+		Where("loans.return_date IS NULL AND loans.status = ? AND loans.recalled_at IS NULL AND loans.due_date > ?", enum.ActiveLoan.String(), dueAfter).
+		Order("loans.loan_date").
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "loans"}, Options: "SKIP LOCKED"}).
+		Take(loan).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("loanID", loan.ID).Info("query executed successfully")
+	return loan, nil
+}
+
+// GetOpenByCopy returns the loan a copy is still out on, whatever its
+// status, as long as it has not been returned.
+func (s *LoanRepositoryImpl) GetOpenByCopy(ctx context.Context, copyID uint) (*model.Loan, error) {
+
+	logger := s.logWithCtx(ctx, "LoanRepository.GetOpenByCopy").
+		WithField("bookCopyID", copyID)
+
+	logger.Info("executing query")
+
+	loan := &model.Loan{}
+
+	err := conn(ctx, s.db).
+		Where("book_copy_id = ? AND return_date IS NULL", copyID).
+		Order("loan_date desc").
+		Take(loan).Error
+	if err != nil {
+		logger.WithError(err).Debug("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("loanID", loan.ID).Info("query executed successfully")
+	return loan, nil
+}
@@ -11,7 +11,11 @@ type MemberRepository interface {
 	Create(ctx context.Context, data *model.Member) (*model.Member, error)
 	Update(ctx context.Context, id uuid.UUID, data *map[string]interface{}) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Member, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*model.Member, error)
 	GetByEmail(ctx context.Context, email string) (*model.Member, error)
+	GetByBarcode(ctx context.Context, barcode string) (*model.Member, error)
 	GetAll(ctx context.Context) (*[]model.Member, error)
+	IncrementPINFailures(ctx context.Context, id uuid.UUID, max int) (int, error)
+	ResetPINFailures(ctx context.Context, id uuid.UUID, max int) error
 	DeleteByID(ctx context.Context, id uuid.UUID) error
 }
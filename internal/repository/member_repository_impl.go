@@ -8,6 +8,7 @@ import (
 	"github.com/nanoLeinz/librarium/internal/model"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MemberRepositoryImpl struct {
@@ -28,7 +29,7 @@ func (s *MemberRepositoryImpl) Create(ctx context.Context, data *model.Member) (
 		"memberID": data.ID,
 	}).Info("Attempting to create a new member")
 
-	result := s.db.WithContext(ctx).Create(data)
+	result := conn(ctx, s.db).Create(data)
 	if result.Error != nil {
 		s.log.WithFields(logrus.Fields{
 			"function": "Create",
@@ -50,7 +51,7 @@ func (s *MemberRepositoryImpl) DeleteByID(ctx context.Context, id uuid.UUID) err
 		"memberID": id.String(),
 	}).Info("Attempting to delete member")
 
-	result := s.db.WithContext(ctx).Delete(&model.Member{}, id)
+	result := conn(ctx, s.db).Delete(&model.Member{}, id)
 	if result.Error != nil {
 		s.log.WithFields(logrus.Fields{
 			"function": "DeleteByID",
@@ -81,7 +82,7 @@ func (s *MemberRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*mode
 	}).Info("Attempting to fetch member by ID")
 
 	var data model.Member
-	result := s.db.WithContext(ctx).First(&data, id)
+	result := conn(ctx, s.db).First(&data, id)
 
 	if result.Error != nil {
 		s.log.WithFields(logrus.Fields{
@@ -98,6 +99,30 @@ func (s *MemberRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*mode
 	return &data, nil
 }
 
+func (s *MemberRepositoryImpl) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*model.Member, error) {
+	s.log.WithFields(logrus.Fields{
+		"function": "GetByIDForUpdate",
+		"memberID": id.String(),
+	}).Info("Attempting to lock member by ID")
+
+	var data model.Member
+	result := conn(ctx, s.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&data, id)
+
+	if result.Error != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "GetByIDForUpdate",
+			"memberID": id.String(),
+		}).WithError(result.Error).Error("Failed to lock member by ID")
+		return nil, result.Error
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"function": "GetByIDForUpdate",
+		"memberID": id.String(),
+	}).Info("Member locked successfully")
+	return &data, nil
+}
+
 func (s *MemberRepositoryImpl) GetByEmail(ctx context.Context, email string) (*model.Member, error) {
 	s.log.WithFields(logrus.Fields{
 		"function": "GetByEmail",
@@ -105,7 +130,7 @@ func (s *MemberRepositoryImpl) GetByEmail(ctx context.Context, email string) (*m
 	}).Info("Attempting to fetch member by email")
 
 	data := &model.Member{}
-	result := s.db.WithContext(ctx).Where("email = ?", email).First(data)
+	result := conn(ctx, s.db).Where("email = ?", email).First(data)
 
 	if result.Error != nil {
 		s.log.WithFields(logrus.Fields{
@@ -123,11 +148,36 @@ func (s *MemberRepositoryImpl) GetByEmail(ctx context.Context, email string) (*m
 	return data, nil
 }
 
+func (s *MemberRepositoryImpl) GetByBarcode(ctx context.Context, barcode string) (*model.Member, error) {
+	s.log.WithFields(logrus.Fields{
+		"function": "GetByBarcode",
+		"barcode":  barcode,
+	}).Info("Attempting to fetch member by barcode")
+
+	data := &model.Member{}
+	result := conn(ctx, s.db).Where("barcode = ?", barcode).First(data)
+
+	if result.Error != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "GetByBarcode",
+			"barcode":  barcode,
+		}).WithError(result.Error).Error("Failed to fetch member by barcode")
+		return nil, result.Error
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"function": "GetByBarcode",
+		"barcode":  barcode,
+		"memberID": data.ID,
+	}).Info("Member fetched successfully")
+	return data, nil
+}
+
 func (s *MemberRepositoryImpl) GetAll(ctx context.Context) (*[]model.Member, error) {
 	s.log.WithField("function", "GetAll").Info("Attempting to fetch all members")
 
 	var data []model.Member
-	result := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Find(&data)
+	result := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Find(&data)
 
 	if result.Error != nil {
 		s.log.WithField("function", "GetAll").WithError(result.Error).Error("Failed to fetch all members")
@@ -148,7 +198,7 @@ func (s *MemberRepositoryImpl) Update(ctx context.Context, id uuid.UUID, data *m
 		"data":     *data,
 	}).Info("Attempting to update member")
 
-	result := s.db.WithContext(ctx).Model(&model.Member{}).Where("id = ?", id).Updates(data)
+	result := conn(ctx, s.db).Model(&model.Member{}).Where("id = ?", id).Updates(data)
 
 	if result.Error != nil {
 		s.log.WithFields(logrus.Fields{
@@ -172,3 +222,80 @@ func (s *MemberRepositoryImpl) Update(ctx context.Context, id uuid.UUID, data *m
 	}).Info("Member updated successfully")
 	return nil
 }
+
+// IncrementPINFailures atomically counts a wrong PIN and returns the new
+// count. It returns gorm.ErrRecordNotFound once the PIN is already locked
+// at max failures.
+func (s *MemberRepositoryImpl) IncrementPINFailures(ctx context.Context, id uuid.UUID, max int) (int, error) {
+	s.log.WithFields(logrus.Fields{
+		"function": "IncrementPINFailures",
+		"memberID": id.String(),
+	}).Info("Attempting to count PIN failure")
+
+	var data model.Member
+	result := conn(ctx, s.db).
+		Model(&data).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "pin_failures"}}}).
+		Where("id = ? AND pin_failures < ?", id, max).
+		UpdateColumn("pin_failures", gorm.Expr("pin_failures + 1"))
+
+	if result.Error != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "IncrementPINFailures",
+			"memberID": id.String(),
+		}).WithError(result.Error).Error("Failed to count PIN failure")
+		return 0, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		s.log.WithFields(logrus.Fields{
+			"function": "IncrementPINFailures",
+			"memberID": id.String(),
+		}).Warn("PIN already locked or member does not exist")
+		return 0, gorm.ErrRecordNotFound
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"function": "IncrementPINFailures",
+		"memberID": id.String(),
+		"failures": data.PINFailures,
+	}).Info("PIN failure counted successfully")
+	return data.PINFailures, nil
+}
+
+// ResetPINFailures clears the member's PIN failures unless the PIN got
+// locked at max failures in the meantime, in which case it returns
+// gorm.ErrRecordNotFound.
+func (s *MemberRepositoryImpl) ResetPINFailures(ctx context.Context, id uuid.UUID, max int) error {
+	s.log.WithFields(logrus.Fields{
+		"function": "ResetPINFailures",
+		"memberID": id.String(),
+	}).Info("Attempting to reset PIN failures")
+
+	result := conn(ctx, s.db).
+		Model(&model.Member{}).
+		Where("id = ? AND pin_failures < ?", id, max).
+		UpdateColumn("pin_failures", 0)
+
+	if result.Error != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "ResetPINFailures",
+			"memberID": id.String(),
+		}).WithError(result.Error).Error("Failed to reset PIN failures")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		s.log.WithFields(logrus.Fields{
+			"function": "ResetPINFailures",
+			"memberID": id.String(),
+		}).Warn("PIN locked or member does not exist")
+		return gorm.ErrRecordNotFound
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"function": "ResetPINFailures",
+		"memberID": id.String(),
+	}).Info("PIN failures reset successfully")
+	return nil
+}
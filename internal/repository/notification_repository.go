@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *model.Notification) error
+	GetByMember(ctx context.Context, memberID uuid.UUID) ([]model.Notification, error)
+	MarkRead(ctx context.Context, id uuid.UUID, memberID uuid.UUID, readAt time.Time) error
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type NotificationRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewNotificationRepository(log *log.Logger, db *gorm.DB) NotificationRepository {
+	return &NotificationRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *NotificationRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *NotificationRepositoryImpl) Create(ctx context.Context, notification *model.Notification) error {
+
+	logger := s.logWithCtx(ctx, "NotificationRepository.Create").
+		WithFields(log.Fields{
+			"memberID": notification.MemberID,
+			"kind":     notification.Kind,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(notification).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("notificationID", notification.ID).Info("query executed successfully")
+	return nil
+}
+
+func (s *NotificationRepositoryImpl) GetByMember(ctx context.Context, memberID uuid.UUID) ([]model.Notification, error) {
+
+	logger := s.logWithCtx(ctx, "NotificationRepository.GetByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("executing query")
+
+	notifications := []model.Notification{}
+
+	err := conn(ctx, s.db).
+		Scopes(helper.Paginator(ctx)).
+		Where("member_id = ?", memberID).
+		Order("created_at desc").
+		Find(&notifications).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(notifications)).Info("query executed successfully")
+	return notifications, nil
+}
+
+func (s *NotificationRepositoryImpl) MarkRead(ctx context.Context, id uuid.UUID, memberID uuid.UUID, readAt time.Time) error {
+
+	logger := s.logWithCtx(ctx, "NotificationRepository.MarkRead").
+		WithFields(log.Fields{
+			"notificationID": id,
+			"memberID":       memberID,
+		})
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).
+		Model(&model.Notification{}).
+		Where("id = ? AND member_id = ?", id, memberID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", readAt))
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	} else if result.RowsAffected < 1 {
+		logger.Warn("no notification found for member")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type PatronSessionRepository interface {
+	Create(ctx context.Context, session *model.PatronSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.PatronSession, error)
+	Touch(ctx context.Context, id uuid.UUID, at time.Time) error
+	End(ctx context.Context, id uuid.UUID, at time.Time) error
+}
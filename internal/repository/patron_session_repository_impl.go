@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PatronSessionRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewPatronSessionRepository(log *log.Logger, db *gorm.DB) PatronSessionRepository {
+	return &PatronSessionRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *PatronSessionRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *PatronSessionRepositoryImpl) Create(ctx context.Context, session *model.PatronSession) error {
+
+	logger := s.logWithCtx(ctx, "PatronSessionRepository.Create").
+		WithFields(log.Fields{
+			"memberID": session.MemberID,
+			"staffID":  session.StaffID,
+		})
+
+	logger.Info("executing query")
+
+	if err := conn(ctx, s.db).Create(session).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.WithField("sessionID", session.ID).Info("query executed successfully")
+	return nil
+}
+
+func (s *PatronSessionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.PatronSession, error) {
+
+	logger := s.logWithCtx(ctx, "PatronSessionRepository.GetByID").
+		WithField("sessionID", id)
+
+	logger.Info("executing query")
+
+	session := model.PatronSession{}
+
+	if err := conn(ctx, s.db).First(&session, "id = ?", id).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.Info("query executed successfully")
+	return &session, nil
+}
+
+func (s *PatronSessionRepositoryImpl) Touch(ctx context.Context, id uuid.UUID, at time.Time) error {
+
+	logger := s.logWithCtx(ctx, "PatronSessionRepository.Touch").
+		WithField("sessionID", id)
+
+	logger.Info("executing query")
+
+	err := conn(ctx, s.db).
+		Model(&model.PatronSession{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("last_scan_at", at).Error
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+func (s *PatronSessionRepositoryImpl) End(ctx context.Context, id uuid.UUID, at time.Time) error {
+
+	logger := s.logWithCtx(ctx, "PatronSessionRepository.End").
+		WithField("sessionID", id)
+
+	logger.Info("executing query")
+
+	result := conn(ctx, s.db).
+		Model(&model.PatronSession{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", at)
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed executing query")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Debug("query executed but 0 rows affected")
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nanoLeinz/librarium/internal/model"
@@ -15,6 +16,16 @@ type ReservationRepository interface {
 	Update(ctx context.Context, reservation model.Reservation) error
 	DeleteById(ctx context.Context, id uuid.UUID) error
 	GetAll(ctx context.Context) ([]model.Reservation, error)
-	GetLastQueue(ctx context.Context, bookID uuid.UUID) int
-	UpdateRelatedQueue(ctx context.Context, bookID uuid.UUID) error
+	GetByMember(ctx context.Context, memberID uuid.UUID) ([]model.Reservation, error)
+	CountActive(ctx context.Context, memberID uuid.UUID, queueKey *string) (int64, error)
+	LockQueue(ctx context.Context, queueKey string) error
+	GetLastQueue(ctx context.Context, queueKey string) (int, error)
+	RecompactQueue(ctx context.Context, queueKey string) error
+	GetQueue(ctx context.Context, queueKeys []string) ([]model.Reservation, error)
+	GetNextInQueue(ctx context.Context, queueKeys []string) (*model.Reservation, error)
+	MarkReady(ctx context.Context, id uuid.UUID, copyID uint, readyAt time.Time, deadline time.Time) error
+	GetReadyByCopy(ctx context.Context, copyID uint) (*model.Reservation, error)
+	GetExpiredHolds(ctx context.Context, asOf time.Time) ([]model.Reservation, error)
+	GetAwaitingRecall(ctx context.Context, placedBefore time.Time) ([]model.Reservation, error)
+	GetStale(ctx context.Context, asOf time.Time, createdBefore *time.Time) ([]model.Reservation, error)
 }
@@ -42,18 +42,24 @@ func (s *ReservationRepositoryImpl) Create(ctx context.Context, reservation *mod
 		WithFields(log.Fields{
 			"bookID":   reservation.BookID,
 			"memberID": reservation.MemberID,
+			"queueKey": reservation.QueueKey,
 			"status":   reservation.Status,
 		})
 
 	logger.Info("executing reservation insert query")
 
-	result := s.db.WithContext(ctx).Exec("INSERT INTO reservations (book_id,member_id,status,queue_position,reservation_date,created_at) VALUES (?,?,?,?,?,?)",
+	result := conn(ctx, s.db).Raw("INSERT INTO reservations (book_id,member_id,book_copy_id,hold_type,queue_key,status,queue_position,reservation_date,not_needed_after,created_at) VALUES (?,?,?,?,?,?,?,?,?,?) RETURNING id",
 		reservation.BookID.String(),
 		reservation.MemberID.String(),
+		reservation.BookCopyID,
+		reservation.HoldType,
+		reservation.QueueKey,
 		reservation.Status,
 		reservation.QueuePosition,
 		reservation.ReservationDate,
-		reservation.ReservationDate)
+		reservation.NotNeededAfter,
+		reservation.ReservationDate).
+		Scan(&reservation.ID)
 
 	err := result.Error
 	if err != nil {
@@ -75,7 +81,7 @@ func (s *ReservationRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (
 	logger.Info("executing get reservation by ID query")
 
 	resv := model.Reservation{}
-	result := s.db.WithContext(ctx).Raw("SELECT * FROM reservations WHERE id = ? and deleted_at IS NULL", id).Scan(&resv)
+	result := conn(ctx, s.db).Raw("SELECT * FROM reservations WHERE id = ? and deleted_at IS NULL", id).Scan(&resv)
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed to get reservation by ID")
 		return nil, result.Error
@@ -94,7 +100,7 @@ func (s *ReservationRepositoryImpl) Update(ctx context.Context, reservation mode
 
 	logger.Info("executing reservation update query")
 
-	result := s.db.WithContext(ctx).
+	result := conn(ctx, s.db).
 		Exec("UPDATE reservations SET status = ?, updated_at = ? WHERE id = ? and deleted_at IS NULL",
 			reservation.Status,
 			reservation.UpdatedAt,
@@ -119,7 +125,7 @@ func (s *ReservationRepositoryImpl) DeleteById(ctx context.Context, id uuid.UUID
 
 	logger.Info("executing reservation delete query")
 
-	result := s.db.WithContext(ctx).
+	result := conn(ctx, s.db).
 		Exec("UPDATE reservations SET deleted_at = ? WHERE id = ? ",
 			time.Now().Local(),
 			id)
@@ -141,7 +147,7 @@ func (s *ReservationRepositoryImpl) GetAll(ctx context.Context) ([]model.Reserva
 	logger.Info("executing get all reservations query")
 
 	resv := []model.Reservation{}
-	result := s.db.WithContext(ctx).Scopes(helper.Paginator(ctx)).Raw("SELECT * FROM reservations WHERE deleted_at IS NULL").Scan(&resv)
+	result := conn(ctx, s.db).Scopes(helper.Paginator(ctx)).Raw("SELECT * FROM reservations WHERE deleted_at IS NULL").Scan(&resv)
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed to get all reservations")
 		return nil, result.Error
@@ -154,60 +160,326 @@ func (s *ReservationRepositoryImpl) GetAll(ctx context.Context) ([]model.Reserva
 	return resv, nil
 }
 
-func (s *ReservationRepositoryImpl) GetLastQueue(ctx context.Context, bookID uuid.UUID) int {
+func (s *ReservationRepositoryImpl) GetByMember(ctx context.Context, memberID uuid.UUID) ([]model.Reservation, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.GetByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("executing get reservations by member query")
+
+	resv := []model.Reservation{}
+	result := conn(ctx, s.db).
+		Scopes(helper.Paginator(ctx)).
+		Where("member_id = ?", memberID).
+		Order("created_at DESC").
+		Find(&resv)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed to get reservations by member")
+		return nil, result.Error
+	}
+
+	logger.WithField("count", len(resv)).Info("member reservations fetched successfully")
+	return resv, nil
+}
+
+// CountActive counts a member's pending and ready reservations, limited to
+// one queue when queueKey is given.
+func (s *ReservationRepositoryImpl) CountActive(ctx context.Context, memberID uuid.UUID, queueKey *string) (int64, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.CountActive").
+		WithFields(log.Fields{
+			"memberID": memberID,
+			"queueKey": queueKey,
+		})
+
+	logger.Info("executing count active reservations query")
+
+	var count int64
+
+	query := conn(ctx, s.db).
+		Model(&model.Reservation{}).
+		Where("member_id = ? AND status IN (?, ?)", memberID, enum.PendingReserv.String(), enum.ReadyReserv.String())
+
+	if queueKey != nil {
+		query = query.Where("queue_key = ?", *queueKey)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		logger.WithError(err).Error("failed to count active reservations")
+		return 0, err
+	}
+
+	logger.WithField("count", count).Info("active reservations counted successfully")
+	return count, nil
+}
+
+// LockQueue serialises changes to one queue until the surrounding
+// transaction ends. It must run inside a transaction.
+func (s *ReservationRepositoryImpl) LockQueue(ctx context.Context, queueKey string) error {
+	logger := s.logWithCtx(ctx, "ReservationRepository.LockQueue").
+		WithField("queueKey", queueKey)
+
+	logger.Info("executing lock queue query")
+
+	result := conn(ctx, s.db).
+		Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "reservation-queue:"+queueKey)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed to lock reservation queue")
+		return result.Error
+	}
+
+	logger.Info("reservation queue locked")
+	return nil
+}
+
+func (s *ReservationRepositoryImpl) GetLastQueue(ctx context.Context, queueKey string) (int, error) {
 	logger := s.logWithCtx(ctx, "ReservationRepository.GetLatestQueue").
-		WithField("bookID", bookID.String())
+		WithField("queueKey", queueKey)
 
 	logger.Info("executing get latest queue query")
 
 	var last int
-	result := s.db.WithContext(ctx).
-		Raw("SELECT COALESCE(MAX(queue_position), 0) FROM reservations WHERE book_id = ? and deleted_at IS NULL", bookID.String()).
+	result := conn(ctx, s.db).
+		Raw("SELECT COALESCE(MAX(queue_position), 0) FROM reservations WHERE queue_key = ? AND status = ? AND deleted_at IS NULL",
+			queueKey,
+			enum.PendingReserv.String()).
 		Scan(&last)
 
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("failed to fetch latest queue position")
-		return 0
+		return 0, result.Error
 	}
 
 	logger.WithField("lastQueue", last).Info("latest queue position fetched successfully")
-	return last
+	return last, nil
 }
 
-func (s *ReservationRepositoryImpl) UpdateRelatedQueue(ctx context.Context, bookID uuid.UUID) error {
-	logger := s.logWithCtx(ctx, "ReservationRepository.UpdateRelatedQueue").
-		WithField("bookID", bookID.String())
-
-	logger.Info("executing update related queue query")
-
-	result := s.db.WithContext(ctx).
-		Exec(`With target as MATERIALIZED (
-		SELECT book_id, queue_position FROM reservations
-		WHERE id = ?)
+// RecompactQueue renumbers the pending reservations of a queue 1..N in
+// their current order and clears the position of everything else.
+func (s *ReservationRepositoryImpl) RecompactQueue(ctx context.Context, queueKey string) error {
+	logger := s.logWithCtx(ctx, "ReservationRepository.RecompactQueue").
+		WithField("queueKey", queueKey)
+
+	logger.Info("executing recompact queue query")
+
+	result := conn(ctx, s.db).
+		Exec(`WITH ranked AS (
+			SELECT id,
+				CASE WHEN status = ? AND deleted_at IS NULL
+					THEN ROW_NUMBER() OVER (
+						PARTITION BY (status = ? AND deleted_at IS NULL)
+						ORDER BY queue_position, reservation_date, created_at)
+					ELSE 0
+				END AS position
+			FROM reservations
+			WHERE queue_key = ?)
 		UPDATE reservations AS r
-		SET queue_position = r.queue_position - 1,
+		SET queue_position = ranked.position,
 			updated_at = ?
-		FROM target AS t
-		WHERE r.book_id = t.book_id
-		AND r.queue_position > t.queue_position
-		AND r.status = ?
-		AND r.deleted_at IS NULL`,
-			bookID,
-			time.Now().Local(),
+		FROM ranked
+		WHERE r.id = ranked.id
+		AND r.queue_position <> ranked.position`,
+			enum.PendingReserv.String(),
 			enum.PendingReserv.String(),
+			queueKey,
+			time.Now().Local(),
 		)
 
 	if result.Error != nil {
-		logger.WithError(result.Error).Error("failed to update related queue")
+		logger.WithError(result.Error).Error("failed to recompact queue")
 		return result.Error
 	}
 
-	logger.WithField("rowsAffected", result.RowsAffected).Info("update related queue executed")
-	if result.RowsAffected < 1 {
-		logger.WithField("rowsAffected", result.RowsAffected).Warn("update affected no rows")
-	} else {
-		logger.WithField("rowsAffected", result.RowsAffected).Info("related queue updated successfully")
+	logger.WithField("rowsAffected", result.RowsAffected).Info("queue recompacted successfully")
+	return nil
+}
+
+// GetQueue lists the pending reservations of the given queues in the
+// order they would be filled.
+func (s *ReservationRepositoryImpl) GetQueue(ctx context.Context, queueKeys []string) ([]model.Reservation, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.GetQueue").
+		WithField("queueKeys", queueKeys)
+
+	logger.Info("executing get queue query")
+
+	resv := []model.Reservation{}
+	result := conn(ctx, s.db).
+		Raw(`SELECT * FROM reservations
+		WHERE queue_key IN ? AND status = ? AND deleted_at IS NULL
+		ORDER BY reservation_date, created_at`,
+			queueKeys,
+			enum.PendingReserv.String()).
+		Scan(&resv)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed to get queue")
+		return nil, result.Error
+	}
+
+	logger.WithField("count", len(resv)).Info("queue fetched successfully")
+	return resv, nil
+}
+
+// GetNextInQueue returns the oldest pending reservation across the given
+// queues. Each queue is kept in placement order, so this is the head of
+// one of them.
+func (s *ReservationRepositoryImpl) GetNextInQueue(ctx context.Context, queueKeys []string) (*model.Reservation, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.GetNextInQueue").
+		WithField("queueKeys", queueKeys)
+
+	logger.Info("executing get next in queue query")
+
+	resv := model.Reservation{}
+	result := conn(ctx, s.db).
+		Raw(`SELECT * FROM reservations
+		WHERE queue_key IN ? AND status = ? AND deleted_at IS NULL
+		ORDER BY reservation_date, created_at
+		LIMIT 1`,
+			queueKeys,
+			enum.PendingReserv.String()).
+		Scan(&resv)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed to get next reservation in queue")
+		return nil, result.Error
+	} else if result.RowsAffected < 1 {
+		logger.Debug("no pending reservation in queue")
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	logger.WithField("reservationID", resv.ID).Info("next reservation in queue fetched successfully")
+	return &resv, nil
+}
+
+func (s *ReservationRepositoryImpl) MarkReady(ctx context.Context, id uuid.UUID, copyID uint, readyAt time.Time, deadline time.Time) error {
+	logger := s.logWithCtx(ctx, "ReservationRepository.MarkReady").
+		WithFields(log.Fields{
+			"reservationID":  id,
+			"bookCopyID":     copyID,
+			"pickupDeadline": deadline,
+		})
+
+	logger.Info("executing mark reservation ready query")
+
+	result := conn(ctx, s.db).
+		Exec(`UPDATE reservations
+		SET status = ?, book_copy_id = ?, ready_at = ?, pickup_deadline = ?, updated_at = ?
+		WHERE id = ? AND status = ? AND deleted_at IS NULL`,
+			enum.ReadyReserv.String(),
+			copyID,
+			readyAt,
+			deadline,
+			readyAt,
+			id,
+			enum.PendingReserv.String())
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed to mark reservation ready")
+		return result.Error
+	} else if result.RowsAffected < 1 {
+		logger.Warn("mark ready query executed but no rows affected")
+		return gorm.ErrRecordNotFound
 	}
 
+	logger.Info("reservation marked ready successfully")
 	return nil
 }
+
+func (s *ReservationRepositoryImpl) GetReadyByCopy(ctx context.Context, copyID uint) (*model.Reservation, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.GetReadyByCopy").
+		WithField("bookCopyID", copyID)
+
+	logger.Info("executing get ready reservation by copy query")
+
+	resv := model.Reservation{}
+	result := conn(ctx, s.db).
+		Raw("SELECT * FROM reservations WHERE book_copy_id = ? AND status = ? AND deleted_at IS NULL LIMIT 1",
+			copyID,
+			enum.ReadyReserv.String()).
+		Scan(&resv)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed to get ready reservation by copy")
+		return nil, result.Error
+	} else if result.RowsAffected < 1 {
+		logger.Debug("no ready reservation for copy")
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	logger.WithField("reservationID", resv.ID).Info("ready reservation fetched successfully")
+	return &resv, nil
+}
+
+func (s *ReservationRepositoryImpl) GetExpiredHolds(ctx context.Context, asOf time.Time) ([]model.Reservation, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.GetExpiredHolds").
+		WithField("asOf", asOf)
+
+	logger.Info("executing get expired holds query")
+
+	resv := []model.Reservation{}
+	result := conn(ctx, s.db).
+		Raw("SELECT * FROM reservations WHERE status = ? AND pickup_deadline < ? AND deleted_at IS NULL ORDER BY pickup_deadline",
+			enum.ReadyReserv.String(),
+			asOf).
+		Scan(&resv)
+
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("failed to get expired holds")
+		return nil, result.Error
+	}
+
+	logger.WithField("count", len(resv)).Info("expired holds fetched successfully")
+	return resv, nil
+}
+
+// GetAwaitingRecall lists pending reservations placed before placedBefore
+// that have not had a loan recalled for them yet.
+func (s *ReservationRepositoryImpl) GetAwaitingRecall(ctx context.Context, placedBefore time.Time) ([]model.Reservation, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.GetAwaitingRecall").
+		WithField("placedBefore", placedBefore)
+
+	logger.Info("executing get reservations awaiting recall query")
+
+	resv := []model.Reservation{}
+	err := conn(ctx, s.db).
+		Where("status = ? AND reservation_date < ?", enum.PendingReserv.String(), placedBefore).
+		Where("NOT EXISTS (SELECT 1 FROM recalls WHERE recalls.reservation_id = reservations.id)").
+		Order("reservation_date").
+		Find(&resv).Error
+	if err != nil {
+		logger.WithError(err).Error("failed to get reservations awaiting recall")
+		return nil, err
+	}
+
+	logger.WithField("count", len(resv)).Info("reservations awaiting recall fetched successfully")
+	return resv, nil
+}
+
+// GetStale lists pending reservations that are no longer needed as of asOf,
+// or that were placed before createdBefore when it is given.
+func (s *ReservationRepositoryImpl) GetStale(ctx context.Context, asOf time.Time, createdBefore *time.Time) ([]model.Reservation, error) {
+	logger := s.logWithCtx(ctx, "ReservationRepository.GetStale").
+		WithFields(log.Fields{
+			"asOf":          asOf,
+			"createdBefore": createdBefore,
+		})
+
+	logger.Info("executing get stale reservations query")
+
+	resv := []model.Reservation{}
+
+	query := conn(ctx, s.db).Where("status = ?", enum.PendingReserv.String())
+	if createdBefore != nil {
+		query = query.Where("not_needed_after < ? OR reservation_date < ?", asOf, *createdBefore)
+	} else {
+		query = query.Where("not_needed_after < ?", asOf)
+	}
+
+	if err := query.Order("queue_key, queue_position").Find(&resv).Error; err != nil {
+		logger.WithError(err).Error("failed to get stale reservations")
+		return nil, err
+	}
+
+	logger.WithField("count", len(resv)).Info("stale reservations fetched successfully")
+	return resv, nil
+}
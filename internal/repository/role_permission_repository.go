@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/model"
+)
+
+type RolePermissionRepository interface {
+	GetByRole(ctx context.Context, role string) ([]model.RolePermission, error)
+	GetAll(ctx context.Context) ([]model.RolePermission, error)
+	ReplaceRole(ctx context.Context, role string, permissions []string) error
+	SeedRole(ctx context.Context, role string, defaults []string) ([]string, error)
+}
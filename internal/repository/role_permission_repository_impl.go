@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RolePermissionRepositoryImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewRolePermissionRepository(log *log.Logger, db *gorm.DB) RolePermissionRepository {
+	return &RolePermissionRepositoryImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *RolePermissionRepositoryImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *RolePermissionRepositoryImpl) GetByRole(ctx context.Context, role string) ([]model.RolePermission, error) {
+
+	logger := s.logWithCtx(ctx, "RolePermissionRepository.GetByRole").
+		WithField("role", role)
+
+	logger.Info("executing query")
+
+	perms := []model.RolePermission{}
+
+	if err := conn(ctx, s.db).Where("role = ?", role).Order("permission").Find(&perms).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(perms)).Info("query executed successfully")
+	return perms, nil
+}
+
+func (s *RolePermissionRepositoryImpl) GetAll(ctx context.Context) ([]model.RolePermission, error) {
+
+	logger := s.logWithCtx(ctx, "RolePermissionRepository.GetAll")
+
+	logger.Info("executing query")
+
+	perms := []model.RolePermission{}
+
+	if err := conn(ctx, s.db).Order("role, permission").Find(&perms).Error; err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("count", len(perms)).Info("query executed successfully")
+	return perms, nil
+}
+
+func (s *RolePermissionRepositoryImpl) ReplaceRole(ctx context.Context, role string, permissions []string) error {
+
+	logger := s.logWithCtx(ctx, "RolePermissionRepository.ReplaceRole").
+		WithFields(log.Fields{
+			"role":        role,
+			"permissions": permissions,
+		})
+
+	logger.Info("executing query")
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Where("role = ?", role).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		perms := []model.RolePermission{}
+		for _, v := range permissions {
+			perms = append(perms, model.RolePermission{
+				Role:       role,
+				Permission: v,
+			})
+		}
+
+		return tx.Create(&perms).Error
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return err
+	}
+
+	logger.Info("query executed successfully")
+	return nil
+}
+
+// SeedRole grants role the defaults that were never seeded for it before
+// and marks them seeded, returning the ones it granted. A role that already
+// holds permissions but has no seed marks was seeded by an earlier release,
+// so its defaults are only marked: the stored permissions may be an admin's
+// edits.
+func (s *RolePermissionRepositoryImpl) SeedRole(ctx context.Context, role string, defaults []string) ([]string, error) {
+
+	logger := s.logWithCtx(ctx, "RolePermissionRepository.SeedRole").
+		WithFields(log.Fields{
+			"role":     role,
+			"defaults": defaults,
+		})
+
+	logger.Info("executing query")
+
+	if len(defaults) == 0 {
+		return []string{}, nil
+	}
+
+	granted := []string{}
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+
+		var seeded, held int64
+		if err := tx.Model(&model.RolePermissionSeed{}).Where("role = ?", role).Count(&seeded).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.RolePermission{}).Where("role = ?", role).Count(&held).Error; err != nil {
+			return err
+		}
+
+		marked := []string{}
+		for _, v := range defaults {
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "role"}, {Name: "permission"}},
+				DoNothing: true,
+			}).Create(&model.RolePermissionSeed{
+				Role:       role,
+				Permission: v,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				marked = append(marked, v)
+			}
+		}
+
+		if seeded == 0 && held > 0 {
+			return nil
+		}
+
+		perms := []model.RolePermission{}
+		for _, v := range marked {
+			perms = append(perms, model.RolePermission{
+				Role:       role,
+				Permission: v,
+			})
+		}
+		granted = marked
+
+		if len(perms) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "role"}, {Name: "permission"}},
+			DoNothing: true,
+		}).Create(&perms).Error
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("failed executing query")
+		return nil, err
+	}
+
+	logger.WithField("granted", granted).Info("query executed successfully")
+	return granted, nil
+}
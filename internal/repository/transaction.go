@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/helper"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TransactorImpl struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+func NewTransactor(log *log.Logger, db *gorm.DB) Transactor {
+	return &TransactorImpl{
+		log: log,
+		db:  db,
+	}
+}
+
+// WithinTransaction runs fn with a transaction stored in its context; every
+// repository called with that context joins it. Nested calls reuse the
+// outer transaction.
+func (s *TransactorImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(helper.KeyCon("tx")).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, helper.KeyCon("tx"), tx))
+	})
+}
+
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(helper.KeyCon("tx")).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
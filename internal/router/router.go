@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/nanoLeinz/librarium/internal/controller"
+	"github.com/nanoLeinz/librarium/internal/enum"
 	m "github.com/nanoLeinz/librarium/internal/middleware"
 )
 
@@ -14,52 +15,126 @@ func NewRouter(member *controller.MemberController,
 	copy *controller.BookCopyController,
 	loan *controller.LoanController,
 	reservation *controller.ReservationController,
+	permission *controller.PermissionController,
+	fine *controller.FineController,
+	policy *controller.CirculationPolicyController,
+	calendar *controller.CalendarController,
+	notification *controller.NotificationController,
+	circulationDesk *controller.CirculationController,
+	kiosk *controller.KioskController,
 ) *http.ServeMux {
 
 	subroute := http.NewServeMux()
 
+	//authorization
+	admin := m.RequireRole(enum.RoleAdmin)
+	catalog := m.RequirePermission(enum.PermCatalogWrite)
+	desk := m.RequirePermission(enum.PermCirculationCheckout)
+	circulation := m.RequirePermission(enum.PermCirculationManage)
+	members := m.RequirePermission(enum.PermMembersManage)
+	cashier := m.RequirePermission(enum.PermFinesCollect)
+	waiver := m.RequirePermission(enum.PermFinesWaive)
+
 	//member
 	subroute.Handle("GET /me", m.GenerateTraceID(http.HandlerFunc(member.Profile)))
 	subroute.Handle("DELETE /me", m.GenerateTraceID(http.HandlerFunc(member.DeleteProfile)))
 	subroute.Handle("PATCH /me", m.GenerateTraceID(http.HandlerFunc(member.UpdateMember)))
+	subroute.Handle("GET /me/loans", m.GenerateTraceID(m.Paginator(http.HandlerFunc(loan.GetMyLoans))))
+	subroute.Handle("GET /me/history", m.GenerateTraceID(m.Paginator(http.HandlerFunc(loan.GetMyHistory))))
+	subroute.Handle("GET /me/reservations", m.GenerateTraceID(m.Paginator(http.HandlerFunc(reservation.GetMyReservations))))
+	subroute.Handle("DELETE /me/reservations/{id}", m.GenerateTraceID(http.HandlerFunc(reservation.CancelMyReservation)))
+	subroute.Handle("GET /me/fines", m.GenerateTraceID(m.Paginator(http.HandlerFunc(fine.GetMyFines))))
+	subroute.Handle("GET /me/notifications", m.GenerateTraceID(m.Paginator(http.HandlerFunc(notification.GetMyNotifications))))
+	subroute.Handle("POST /me/notifications/{id}/read", m.GenerateTraceID(http.HandlerFunc(notification.MarkNotificationRead)))
+	subroute.Handle("GET /members", m.GenerateTraceID(members(m.Paginator(http.HandlerFunc(member.GetAllMembers)))))
+	subroute.Handle("PATCH /members/{id}", m.GenerateTraceID(members(http.HandlerFunc(member.ManageMember))))
+	subroute.Handle("GET /members/lookup", m.GenerateTraceID(desk(http.HandlerFunc(member.GetMemberByBarcode))))
+	subroute.Handle("PUT /me/pin", m.GenerateTraceID(http.HandlerFunc(member.SetMyPIN)))
+	subroute.Handle("PUT /members/{id}/pin", m.GenerateTraceID(members(http.HandlerFunc(member.SetMemberPIN))))
 
 	//author
-	subroute.Handle("POST /author", m.GenerateTraceID(http.HandlerFunc(author.CreateAuthor)))
+	subroute.Handle("POST /author", m.GenerateTraceID(catalog(http.HandlerFunc(author.CreateAuthor))))
 	subroute.Handle("GET /author/{id}", m.GenerateTraceID(http.HandlerFunc(author.GetByID)))
 	subroute.Handle("GET /author", m.GenerateTraceID(m.Paginator(http.HandlerFunc(author.GetAllAuthor))))
-	subroute.Handle("DELETE /author/{id}", m.GenerateTraceID(http.HandlerFunc(author.DeleteByID)))
-	subroute.Handle("PATCH /author/{id}", m.GenerateTraceID(http.HandlerFunc(author.UpdateAuthor)))
+	subroute.Handle("DELETE /author/{id}", m.GenerateTraceID(catalog(http.HandlerFunc(author.DeleteByID))))
+	subroute.Handle("PATCH /author/{id}", m.GenerateTraceID(catalog(http.HandlerFunc(author.UpdateAuthor))))
 	subroute.Handle("GET /author/{id}/books", m.GenerateTraceID(http.HandlerFunc(author.GetAuthorsBook)))
 
 	//book
-	subroute.Handle("POST /book", m.GenerateTraceID(http.HandlerFunc(book.CreateBook)))
-	subroute.Handle("DELETE /book/{id}", m.GenerateTraceID(http.HandlerFunc(book.DeleteBook)))
-	subroute.Handle("PATCH /book/{id}", m.GenerateTraceID(http.HandlerFunc(book.UpdateBook)))
+	subroute.Handle("POST /book", m.GenerateTraceID(catalog(http.HandlerFunc(book.CreateBook))))
+	subroute.Handle("DELETE /book/{id}", m.GenerateTraceID(catalog(http.HandlerFunc(book.DeleteBook))))
+	subroute.Handle("PATCH /book/{id}", m.GenerateTraceID(catalog(http.HandlerFunc(book.UpdateBook))))
 	subroute.Handle("GET /book/{id}", m.GenerateTraceID(http.HandlerFunc(book.GetBook)))
 	subroute.Handle("GET /book", m.GenerateTraceID(m.Paginator(http.HandlerFunc(book.GetAll))))
 	subroute.Handle("GET /book/search", m.GenerateTraceID(m.Paginator(http.HandlerFunc(book.GetBookByTitle))))
 
 	//book copy
-	subroute.Handle("POST /book/{bookID}/copies", m.GenerateTraceID(http.HandlerFunc(copy.CreateCopies)))
-	subroute.Handle("DELETE /book/{bookID}/copies/{copyID}", m.GenerateTraceID(http.HandlerFunc(copy.DeleteCopy)))
-	subroute.Handle("PATCH /book/{bookID}/copies/{copyID}", m.GenerateTraceID(http.HandlerFunc(copy.UpdateStatus)))
+	subroute.Handle("POST /book/{bookID}/copies", m.GenerateTraceID(catalog(http.HandlerFunc(copy.CreateCopies))))
+	subroute.Handle("DELETE /book/{bookID}/copies/{copyID}", m.GenerateTraceID(catalog(http.HandlerFunc(copy.DeleteCopy))))
+	subroute.Handle("PATCH /book/{bookID}/copies/{copyID}", m.GenerateTraceID(catalog(http.HandlerFunc(copy.UpdateStatus))))
 	subroute.Handle("GET /book/{bookID}/copies", m.GenerateTraceID(m.Paginator(http.HandlerFunc(copy.GetCopyByCondition))))
 	subroute.Handle("GET /book/copies", m.GenerateTraceID(m.Paginator(http.HandlerFunc(copy.GetAll))))
+	subroute.Handle("GET /book/copies/lookup", m.GenerateTraceID(desk(http.HandlerFunc(copy.GetCopyByBarcode))))
+	subroute.Handle("GET /book/copies/shelf-search", m.GenerateTraceID(desk(m.Paginator(http.HandlerFunc(copy.GetShelfSearch)))))
 	subroute.Handle("GET /book/{bookID}/copies/{copyID}", m.GenerateTraceID(http.HandlerFunc(copy.GetCopy)))
+	subroute.Handle("GET /book/{bookID}/copies/{copyID}/history", m.GenerateTraceID(desk(m.Paginator(http.HandlerFunc(copy.GetHistory)))))
+
+	//circulation desk
+	subroute.Handle("POST /circulation/sessions", m.GenerateTraceID(desk(http.HandlerFunc(circulationDesk.StartSession))))
+	subroute.Handle("DELETE /circulation/sessions/{id}", m.GenerateTraceID(desk(http.HandlerFunc(circulationDesk.EndSession))))
+	subroute.Handle("POST /circulation/scan", m.GenerateTraceID(desk(http.HandlerFunc(circulationDesk.Scan))))
+
+	//kiosk
+	subroute.Handle("POST /kiosks", m.GenerateTraceID(admin(http.HandlerFunc(kiosk.CreateKiosk))))
+	subroute.Handle("GET /kiosks", m.GenerateTraceID(admin(http.HandlerFunc(kiosk.GetKiosks))))
+	subroute.Handle("DELETE /kiosks/{id}", m.GenerateTraceID(admin(http.HandlerFunc(kiosk.DeactivateKiosk))))
 
 	//loan
-	subroute.Handle("POST /loans", m.GenerateTraceID(http.HandlerFunc(loan.CreateLoan)))
-	subroute.Handle("DELETE /loans/{id}", m.GenerateTraceID(http.HandlerFunc(loan.DeleteLoan)))
-	subroute.Handle("PATCH /loans/{id}", m.GenerateTraceID(http.HandlerFunc(loan.UpdateLoan)))
+	subroute.Handle("POST /loans", m.GenerateTraceID(desk(http.HandlerFunc(loan.CreateLoan))))
+	subroute.Handle("DELETE /loans/{id}", m.GenerateTraceID(circulation(http.HandlerFunc(loan.DeleteLoan))))
+	subroute.Handle("PATCH /loans/{id}", m.GenerateTraceID(desk(http.HandlerFunc(loan.UpdateLoan))))
+	subroute.Handle("POST /loans/{id}/return", m.GenerateTraceID(desk(http.HandlerFunc(loan.ReturnLoan))))
+	subroute.Handle("POST /loans/returns", m.GenerateTraceID(desk(http.HandlerFunc(loan.BatchReturn))))
+	subroute.Handle("POST /loans/{id}/lost", m.GenerateTraceID(http.HandlerFunc(loan.DeclareLost)))
+	subroute.Handle("POST /loans/{id}/claims-returned", m.GenerateTraceID(desk(http.HandlerFunc(loan.ClaimReturned))))
+	subroute.Handle("POST /loans/{id}/claims-returned/resolve", m.GenerateTraceID(circulation(http.HandlerFunc(loan.ResolveClaim))))
+	subroute.Handle("POST /loans/{id}/renew", m.GenerateTraceID(http.HandlerFunc(loan.RenewLoan)))
 	subroute.Handle("GET /loans/{id}", m.GenerateTraceID(http.HandlerFunc(loan.GetLoanByID)))
-	subroute.Handle("GET /loans", m.GenerateTraceID(m.Paginator(http.HandlerFunc(loan.GetAllLoan))))
+	subroute.Handle("GET /loans", m.GenerateTraceID(desk(m.Paginator(http.HandlerFunc(loan.GetAllLoan)))))
 
 	//reservation
 	subroute.Handle("POST /reservation", m.GenerateTraceID(http.HandlerFunc(reservation.CreateReservation)))
-	subroute.Handle("DELETE /reservation/{id}", m.GenerateTraceID(http.HandlerFunc(reservation.DeleteReservation)))
-	subroute.Handle("PATCH /reservation/{id}", m.GenerateTraceID(http.HandlerFunc(reservation.UpdateReservation)))
+	subroute.Handle("DELETE /reservation/{id}", m.GenerateTraceID(desk(http.HandlerFunc(reservation.DeleteReservation))))
+	subroute.Handle("PATCH /reservation/{id}", m.GenerateTraceID(desk(http.HandlerFunc(reservation.UpdateReservation))))
 	subroute.Handle("GET /reservation/{id}", m.GenerateTraceID(http.HandlerFunc(reservation.GetReservationByID)))
-	subroute.Handle("GET /reservation", m.GenerateTraceID(m.Paginator(http.HandlerFunc(reservation.GetAllReservation))))
+	subroute.Handle("POST /reservation/{id}/recall", m.GenerateTraceID(circulation(http.HandlerFunc(loan.RecallForReservation))))
+	subroute.Handle("GET /book/{id}/queue", m.GenerateTraceID(desk(http.HandlerFunc(reservation.GetBookQueue))))
+	subroute.Handle("GET /reservation", m.GenerateTraceID(desk(m.Paginator(http.HandlerFunc(reservation.GetAllReservation)))))
+
+	//fine
+	subroute.Handle("POST /fines", m.GenerateTraceID(cashier(http.HandlerFunc(fine.CreateFine))))
+	subroute.Handle("GET /fines", m.GenerateTraceID(admin(m.Paginator(http.HandlerFunc(fine.GetAllFines)))))
+	subroute.Handle("GET /fines/{id}", m.GenerateTraceID(cashier(http.HandlerFunc(fine.GetFineByID))))
+	subroute.Handle("POST /fines/{id}/payments", m.GenerateTraceID(cashier(http.HandlerFunc(fine.PayFine))))
+	subroute.Handle("POST /fines/{id}/waive", m.GenerateTraceID(waiver(http.HandlerFunc(fine.WaiveFine))))
+	subroute.Handle("GET /members/{id}/fines", m.GenerateTraceID(cashier(m.Paginator(http.HandlerFunc(fine.GetMemberFines)))))
+
+	//circulation policy
+	subroute.Handle("GET /policies", m.GenerateTraceID(admin(http.HandlerFunc(policy.GetPolicies))))
+	subroute.Handle("POST /policies", m.GenerateTraceID(admin(http.HandlerFunc(policy.CreatePolicy))))
+	subroute.Handle("PATCH /policies/{id}", m.GenerateTraceID(admin(http.HandlerFunc(policy.UpdatePolicy))))
+	subroute.Handle("DELETE /policies/{id}", m.GenerateTraceID(admin(http.HandlerFunc(policy.DeletePolicy))))
+
+	//calendar
+	subroute.Handle("GET /calendar", m.GenerateTraceID(http.HandlerFunc(calendar.GetCalendar)))
+	subroute.Handle("PUT /calendar/hours/{weekday}", m.GenerateTraceID(admin(http.HandlerFunc(calendar.SetOpeningHours))))
+	subroute.Handle("POST /calendar/closures", m.GenerateTraceID(admin(http.HandlerFunc(calendar.CreateClosure))))
+	subroute.Handle("POST /calendar/closures/import", m.GenerateTraceID(admin(http.HandlerFunc(calendar.ImportClosures))))
+	subroute.Handle("DELETE /calendar/closures/{id}", m.GenerateTraceID(admin(http.HandlerFunc(calendar.DeleteClosure))))
+
+	//permission
+	subroute.Handle("GET /permissions", m.GenerateTraceID(admin(http.HandlerFunc(permission.GetAll))))
+	subroute.Handle("PUT /permissions/{role}", m.GenerateTraceID(admin(http.HandlerFunc(permission.UpdateRole))))
 
 	//v1 api
 	mainroute := http.NewServeMux()
@@ -67,7 +142,13 @@ func NewRouter(member *controller.MemberController,
 
 	//auth
 	mainroute.HandleFunc("POST /api/v1/members", auth.Register)
-	mainroute.HandleFunc("POST /api/v1/login", auth.Login)
+	mainroute.Handle("POST /api/v1/login", m.GenerateTraceID(http.HandlerFunc(auth.Login)))
+
+	//self-checkout kiosk, authenticated with the kiosk credential instead of a JWT
+	kioskAuth := m.ValidateKiosk(kiosk.Verify)
+	mainroute.Handle("POST /api/v1/kiosk/sessions", m.ExtendContext(m.GenerateTraceID(kioskAuth(http.HandlerFunc(circulationDesk.StartKioskSession)))))
+	mainroute.Handle("DELETE /api/v1/kiosk/sessions/{id}", m.ExtendContext(m.GenerateTraceID(kioskAuth(http.HandlerFunc(circulationDesk.EndSession)))))
+	mainroute.Handle("POST /api/v1/kiosk/scan", m.ExtendContext(m.GenerateTraceID(kioskAuth(http.HandlerFunc(circulationDesk.Scan)))))
 
 	return mainroute
 
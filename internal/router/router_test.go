@@ -0,0 +1,230 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+// access is who a route lets through, given each role's default permissions.
+type access int
+
+const (
+	public access = iota
+	authed
+	staff
+	adminOnly
+	kiosk
+)
+
+type outcome string
+
+const (
+	passed          outcome = "passed"
+	forbidden       outcome = "forbidden"
+	unauthenticated outcome = "unauthenticated"
+	unrouted        outcome = "unrouted"
+)
+
+var routes = []struct {
+	method string
+	path   string
+	access access
+}{
+	{"GET", "/api/v1/me", authed},
+	{"DELETE", "/api/v1/me", authed},
+	{"PATCH", "/api/v1/me", authed},
+	{"GET", "/api/v1/me/loans", authed},
+	{"GET", "/api/v1/me/history", authed},
+	{"GET", "/api/v1/me/reservations", authed},
+	{"DELETE", "/api/v1/me/reservations/{id}", authed},
+	{"GET", "/api/v1/me/fines", authed},
+	{"GET", "/api/v1/me/notifications", authed},
+	{"POST", "/api/v1/me/notifications/{id}/read", authed},
+	{"GET", "/api/v1/members", adminOnly},
+	{"PATCH", "/api/v1/members/{id}", adminOnly},
+	{"GET", "/api/v1/members/lookup", staff},
+	{"PUT", "/api/v1/me/pin", authed},
+	{"PUT", "/api/v1/members/{id}/pin", adminOnly},
+	{"POST", "/api/v1/author", adminOnly},
+	{"GET", "/api/v1/author/{id}", authed},
+	{"GET", "/api/v1/author", authed},
+	{"DELETE", "/api/v1/author/{id}", adminOnly},
+	{"PATCH", "/api/v1/author/{id}", adminOnly},
+	{"GET", "/api/v1/author/{id}/books", authed},
+	{"POST", "/api/v1/book", adminOnly},
+	{"DELETE", "/api/v1/book/{id}", adminOnly},
+	{"PATCH", "/api/v1/book/{id}", adminOnly},
+	{"GET", "/api/v1/book/{id}", authed},
+	{"GET", "/api/v1/book", authed},
+	{"GET", "/api/v1/book/search", authed},
+	{"POST", "/api/v1/book/{bookID}/copies", adminOnly},
+	{"DELETE", "/api/v1/book/{bookID}/copies/{copyID}", adminOnly},
+	{"PATCH", "/api/v1/book/{bookID}/copies/{copyID}", adminOnly},
+	{"GET", "/api/v1/book/{bookID}/copies", authed},
+	{"GET", "/api/v1/book/copies", authed},
+	{"GET", "/api/v1/book/copies/lookup", staff},
+	{"GET", "/api/v1/book/copies/shelf-search", staff},
+	{"GET", "/api/v1/book/{bookID}/copies/{copyID}", authed},
+	{"GET", "/api/v1/book/{bookID}/copies/{copyID}/history", staff},
+	{"POST", "/api/v1/circulation/sessions", staff},
+	{"DELETE", "/api/v1/circulation/sessions/{id}", staff},
+	{"POST", "/api/v1/circulation/scan", staff},
+	{"POST", "/api/v1/kiosks", adminOnly},
+	{"GET", "/api/v1/kiosks", adminOnly},
+	{"DELETE", "/api/v1/kiosks/{id}", adminOnly},
+	{"POST", "/api/v1/loans", staff},
+	{"DELETE", "/api/v1/loans/{id}", adminOnly},
+	{"PATCH", "/api/v1/loans/{id}", staff},
+	{"POST", "/api/v1/loans/{id}/return", staff},
+	{"POST", "/api/v1/loans/returns", staff},
+	{"POST", "/api/v1/loans/{id}/lost", authed},
+	{"POST", "/api/v1/loans/{id}/claims-returned", staff},
+	{"POST", "/api/v1/loans/{id}/claims-returned/resolve", adminOnly},
+	{"POST", "/api/v1/loans/{id}/renew", authed},
+	{"GET", "/api/v1/loans/{id}", authed},
+	{"GET", "/api/v1/loans", staff},
+	{"POST", "/api/v1/reservation", authed},
+	{"DELETE", "/api/v1/reservation/{id}", staff},
+	{"PATCH", "/api/v1/reservation/{id}", staff},
+	{"GET", "/api/v1/reservation/{id}", authed},
+	{"POST", "/api/v1/reservation/{id}/recall", adminOnly},
+	{"GET", "/api/v1/book/{id}/queue", staff},
+	{"GET", "/api/v1/reservation", staff},
+	{"POST", "/api/v1/fines", staff},
+	{"GET", "/api/v1/fines", adminOnly},
+	{"GET", "/api/v1/fines/{id}", staff},
+	{"POST", "/api/v1/fines/{id}/payments", staff},
+	{"POST", "/api/v1/fines/{id}/waive", staff},
+	{"GET", "/api/v1/members/{id}/fines", staff},
+	{"GET", "/api/v1/policies", adminOnly},
+	{"POST", "/api/v1/policies", adminOnly},
+	{"PATCH", "/api/v1/policies/{id}", adminOnly},
+	{"DELETE", "/api/v1/policies/{id}", adminOnly},
+	{"GET", "/api/v1/calendar", authed},
+	{"PUT", "/api/v1/calendar/hours/{weekday}", adminOnly},
+	{"POST", "/api/v1/calendar/closures", adminOnly},
+	{"POST", "/api/v1/calendar/closures/import", adminOnly},
+	{"DELETE", "/api/v1/calendar/closures/{id}", adminOnly},
+	{"GET", "/api/v1/permissions", adminOnly},
+	{"PUT", "/api/v1/permissions/{role}", adminOnly},
+	{"POST", "/api/v1/members", public},
+	{"POST", "/api/v1/login", public},
+	{"POST", "/api/v1/kiosk/sessions", kiosk},
+	{"DELETE", "/api/v1/kiosk/sessions/{id}", kiosk},
+	{"POST", "/api/v1/kiosk/scan", kiosk},
+}
+
+func TestRouteAuthorization(t *testing.T) {
+	log.SetOutput(io.Discard)
+	t.Setenv("SECRETJWT", "router-test-secret")
+
+	librarianPerms := []string{
+		enum.PermCirculationCheckout.String(),
+		enum.PermFinesWaive.String(),
+		enum.PermFinesCollect.String(),
+		enum.PermCirculationOverride.String(),
+	}
+	adminPerms := []string{}
+	for _, v := range enum.AllPermissions() {
+		adminPerms = append(adminPerms, v.String())
+	}
+
+	callers := []struct {
+		name  string
+		token string
+	}{
+		{"anonymous", ""},
+		{"member", token(t, enum.RoleMember, []string{})},
+		{"librarian", token(t, enum.RoleLibrarian, librarianPerms)},
+		{"admin", token(t, enum.RoleAdmin, adminPerms)},
+	}
+
+	expected := map[access]map[string]outcome{
+		public:    {"anonymous": passed, "member": passed, "librarian": passed, "admin": passed},
+		authed:    {"anonymous": unauthenticated, "member": passed, "librarian": passed, "admin": passed},
+		staff:     {"anonymous": unauthenticated, "member": forbidden, "librarian": passed, "admin": passed},
+		adminOnly: {"anonymous": unauthenticated, "member": forbidden, "librarian": forbidden, "admin": passed},
+		kiosk:     {"anonymous": unauthenticated, "member": unauthenticated, "librarian": unauthenticated, "admin": unauthenticated},
+	}
+
+	ids := strings.NewReplacer(
+		"{id}", uuid.NewString(),
+		"{bookID}", uuid.NewString(),
+		"{copyID}", uuid.NewString(),
+		"{weekday}", "1",
+		"{role}", enum.RoleLibrarian.String(),
+	)
+
+	mux := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	for _, route := range routes {
+		for _, caller := range callers {
+			t.Run(route.method+" "+route.path+" as "+caller.name, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, ids.Replace(route.path), nil)
+				if caller.token != "" {
+					req.Header.Set("Authorization", "Bearer "+caller.token)
+				}
+
+				want := expected[route.access][caller.name]
+				if got := serve(mux, req); got != want {
+					t.Errorf("got %s, want %s", got, want)
+				}
+			})
+		}
+	}
+}
+
+func token(t *testing.T, role enum.Role, permissions []string) string {
+	t.Helper()
+
+	member := &dto.MemberResponse{
+		ID:    uuid.New(),
+		Email: role.String() + "@librarium.test",
+		Role:  role.String(),
+	}
+
+	signed, err := helper.GenerateJWTToken(member, permissions)
+	if err != nil {
+		t.Fatalf("generating %s token: %v", role, err)
+	}
+
+	return signed
+}
+
+// serve runs req through the router. The controllers are nil, so any
+// request the middleware lets through panics once it reaches its handler.
+func serve(mux http.Handler, req *http.Request) (result outcome) {
+	rec := httptest.NewRecorder()
+
+	defer func() {
+		if recover() != nil {
+			result = passed
+		}
+	}()
+
+	mux.ServeHTTP(rec, req)
+
+	var res dto.WebResponse
+	json.NewDecoder(rec.Body).Decode(&res)
+
+	switch {
+	case rec.Code == http.StatusForbidden && res.Status == "forbidden":
+		return forbidden
+	case rec.Code == http.StatusBadRequest, rec.Code == http.StatusUnauthorized:
+		return unauthenticated
+	case rec.Code == http.StatusNotFound, rec.Code == http.StatusMethodNotAllowed:
+		return unrouted
+	}
+
+	return passed
+}
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// Job is a periodic task. Run reports how many records it touched.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) (int, error)
+}
+
+// Scheduler runs registered jobs on their own tickers. Every run is guarded
+// by an advisory lock named after the job and checked against the job's
+// recorded runs, so with several replicas runs never overlap and a job
+// executes at most once per interval. Each executed run is recorded.
+type Scheduler struct {
+	log      *log.Logger
+	locker   repository.AdvisoryLocker
+	runRepo  repository.JobRunRepository
+	instance string
+	jobs     []Job
+	wg       sync.WaitGroup
+}
+
+func New(log *log.Logger, locker repository.AdvisoryLocker, runRepo repository.JobRunRepository) *Scheduler {
+	instance, _ := os.Hostname()
+
+	return &Scheduler{
+		log:      log,
+		locker:   locker,
+		runRepo:  runRepo,
+		instance: instance,
+	}
+}
+
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every registered job, each running once immediately and
+// then on its interval until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Wait blocks until every job loop has stopped after ctx is cancelled,
+// including any run still in progress.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs job if no other instance is running it and it has not run
+// this interval. The run itself is not cancelled with ctx, so shutting down
+// lets it finish and record itself instead of stopping it halfway.
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) {
+	jobCtx := context.WithValue(context.WithoutCancel(ctx), helper.KeyCon("traceID"), "JOB-"+job.Name)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  "JOB-" + job.Name,
+		"function": "Scheduler.RunOnce",
+		"job":      job.Name,
+	})
+
+	unlock, acquired, err := s.locker.TryLock(jobCtx, "job:"+job.Name)
+	if err != nil {
+		logger.WithError(err).Error("failed acquiring job lock")
+		return
+	}
+	if !acquired {
+		logger.Info("job is running on another instance, skipping")
+		return
+	}
+	defer unlock()
+
+	// Replicas tick out of phase, so the lock alone would let each of them
+	// run the job once per interval. The slack keeps this replica's own
+	// ticks, which arrive a hair under one interval apart, from skipping.
+	last, err := s.runRepo.LastStartedAt(jobCtx, job.Name)
+	if err != nil {
+		logger.WithError(err).Error("failed reading last job run")
+		return
+	}
+	if time.Since(last) < job.Interval-job.Interval/10 {
+		logger.WithField("lastStartedAt", last).Info("job already ran this interval, skipping")
+		return
+	}
+
+	run := model.JobRun{
+		JobName:   job.Name,
+		Instance:  s.instance,
+		StartedAt: time.Now(),
+	}
+
+	affected, err := s.execute(jobCtx, job)
+
+	run.FinishedAt = time.Now()
+	run.Affected = affected
+	run.Status = enum.SucceededJob.String()
+	if err != nil {
+		run.Status = enum.FailedJob.String()
+		run.Error = err.Error()
+		logger.WithError(err).Error("job failed")
+	} else {
+		logger.WithField("affected", affected).Info("job finished")
+	}
+
+	if err := s.runRepo.Create(jobCtx, &run); err != nil {
+		logger.WithError(err).Error("failed recording job run")
+	}
+}
+
+// execute turns a panicking job into a failed run instead of taking the
+// process down.
+func (s *Scheduler) execute(ctx context.Context, job Job) (affected int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{value: r}
+		}
+	}()
+
+	return job.Run(ctx)
+}
+
+type panicError struct {
+	value any
+}
+
+func (s *panicError) Error() string {
+	return fmt.Sprintf("job panicked: %v", s.value)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/model"
 	"github.com/nanoLeinz/librarium/internal/model/dto"
 	log "github.com/sirupsen/logrus"
 )
@@ -14,6 +16,11 @@ type BookCopyService interface {
 	Update(ctx context.Context, copyId uint, bookCopy *dto.BookCopyRequest) error
 	DeleteById(ctx context.Context, bookCopyId uint) error
 	GetByID(ctx context.Context, bookCopyId uint) (*dto.BookCopyResponse, error)
+	GetByBarcode(ctx context.Context, barcode string) (*dto.BookCopyResponse, error)
 	GetAll(ctx context.Context) (*[]dto.BookCopyResponse, error)
 	GetByCondition(ctx context.Context, bookCopy *dto.BookCopyRequest) (*[]dto.BookCopyResponse, error)
+	GetShelfSearch(ctx context.Context) ([]dto.BookCopyResponse, error)
+	Transition(ctx context.Context, copy *model.BookCopy, to enum.CopyStatus, reason string) error
+	Record(ctx context.Context, event *model.ItemEvent) error
+	GetHistory(ctx context.Context, bookID uuid.UUID, copyID uint) ([]dto.ItemEventResponse, error)
 }
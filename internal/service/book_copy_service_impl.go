@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
 
 	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/model"
 	"github.com/nanoLeinz/librarium/internal/model/dto"
@@ -16,17 +20,35 @@ import (
 var (
 	errIntServer = myerror.InternalServerErr
 	errNotFound  = myerror.NewNotFoundError("copy")
+
+	errCopyStatusInvalid = myerror.NewBadRequestError("copy status invalid")
+	errCopyCirculating   = myerror.NewBadRequestError("loaned and reserved are set by checkout, return and holds")
+	errCopyOnLoan        = myerror.NewBadRequestError("copy is still on loan")
+	errCopyHeld          = myerror.NewBadRequestError("copy is held for a reservation")
+	errCopyBarcode       = myerror.NewBadRequestError("invalid item barcode")
+	errCopyBarcodeTaken  = myerror.NewDuplicateError("copy barcode")
 )
 
+// initialCopyStatuses are the statuses new copies may be created in.
+var initialCopyStatuses = []enum.CopyStatus{enum.AvailableCopy, enum.InTransitCopy, enum.InRepairCopy}
+
 type BookCopyServiceImpl struct {
-	log      *log.Logger
-	copyRepo repository.BookCopyRepository
+	log        *log.Logger
+	tx         repository.Transactor
+	copyRepo   repository.BookCopyRepository
+	loanRepo   repository.LoanRepository
+	reservRepo repository.ReservationRepository
+	eventRepo  repository.ItemEventRepository
 }
 
-func NewBookCopyService(log *log.Logger, copyRepo repository.BookCopyRepository) BookCopyService {
+func NewBookCopyService(log *log.Logger, tx repository.Transactor, copyRepo repository.BookCopyRepository, loanRepo repository.LoanRepository, reservRepo repository.ReservationRepository, eventRepo repository.ItemEventRepository) BookCopyService {
 	return &BookCopyServiceImpl{
-		log:      log,
-		copyRepo: copyRepo,
+		log:        log,
+		tx:         tx,
+		copyRepo:   copyRepo,
+		loanRepo:   loanRepo,
+		reservRepo: reservRepo,
+		eventRepo:  eventRepo,
 	}
 }
 
@@ -53,16 +75,48 @@ func (s *BookCopyServiceImpl) Create(ctx context.Context, bookId uuid.UUID, stat
 
 	logger.Info("received create book copy request")
 
-	err := s.copyRepo.Create(ctx, bookId, status, copies)
+	if status == "" {
+		status = enum.AvailableCopy.String()
+	}
+
+	initial, ok := enum.ParseCopyStatus(status)
+	if !ok || !slices.Contains(initialCopyStatuses, initial) {
+		logger.Warn("invalid initial copy status")
+		return errCopyStatusInvalid
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, err := s.copyRepo.Create(ctx, bookId, status, copies)
+		if err != nil {
+			logger.WithError(err).Error("failed to create book copies")
+			return errIntServer
+		}
+
+		for _, v := range created {
+			err := s.Record(ctx, &model.ItemEvent{
+				BookCopyID: v.ID,
+				Kind:       enum.StatusChangeEvent.String(),
+				ToStatus:   v.Status,
+				Reason:     "copy added",
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
 	if err != nil {
-		logger.WithError(err).Error("failed to create book copies")
-		return errIntServer
+		return myerror.FromError(err)
 	}
 
 	logger.Info("book copies created successfully")
 	return nil
 }
 
+// Update applies a manual change to a copy. Loaned and reserved are set by
+// circulation only, so they can be neither entered nor left here.
 func (s *BookCopyServiceImpl) Update(ctx context.Context, copyId uint, bookCopy *dto.BookCopyRequest) error {
 	logger := s.logWithCtx(ctx, "BookCopyService.Update").
 		WithFields(log.Fields{
@@ -73,41 +127,303 @@ func (s *BookCopyServiceImpl) Update(ctx context.Context, copyId uint, bookCopy
 
 	logger.Info("received update book copy request")
 
-	copy := model.BookCopy{
-		Model: gorm.Model{
-			ID: copyId,
-		},
-		Status: bookCopy.Status,
+	if bookCopy.ReplacementCost != nil && *bookCopy.ReplacementCost < 0 {
+		logger.WithField("replacementCost", *bookCopy.ReplacementCost).Warn("negative replacement cost")
+		return myerror.NewBadRequestError("replacement cost must not be negative")
+	}
+
+	if bookCopy.Barcode != nil && !helper.ValidBarcode(helper.ItemBarcodePrefix(), *bookCopy.Barcode) {
+		logger.WithField("barcode", *bookCopy.Barcode).Warn("invalid item barcode")
+		return errCopyBarcode
+	}
+
+	var to enum.CopyStatus
+	if bookCopy.Status != "" {
+		var ok bool
+		if to, ok = enum.ParseCopyStatus(bookCopy.Status); !ok {
+			logger.Warn("unknown copy status")
+			return errCopyStatusInvalid
+		}
+
+		if to == enum.LoanedCopy || to == enum.ReservedCopy {
+			logger.Warn("circulation status set by hand")
+			return errCopyCirculating
+		}
 	}
 
-	err := s.copyRepo.Update(ctx, &copy)
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		copy, err := s.copyRepo.GetByIDForUpdate(ctx, copyId)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock book copy by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errNotFound
+			default:
+				return errIntServer
+			}
+		}
+
+		events := []model.ItemEvent{}
+		change := func(kind enum.ItemEventKind, from, to string) {
+			if from != to {
+				events = append(events, model.ItemEvent{
+					BookCopyID: copy.ID,
+					Kind:       kind.String(),
+					FromValue:  from,
+					ToValue:    to,
+					Reason:     bookCopy.Reason,
+				})
+			}
+		}
+
+		if bookCopy.ReplacementCost != nil {
+			change(enum.ReplacementCostEvent,
+				strconv.FormatFloat(copy.ReplacementCost, 'f', -1, 64),
+				strconv.FormatFloat(*bookCopy.ReplacementCost, 'f', -1, 64))
+			copy.ReplacementCost = *bookCopy.ReplacementCost
+		}
+
+		if bookCopy.Barcode != nil {
+			other, err := s.copyRepo.GetByBarcode(ctx, *bookCopy.Barcode)
+			if err != nil && err != gorm.ErrRecordNotFound {
+				logger.WithError(err).Error("failed to get book copy by barcode")
+				return errIntServer
+			}
+
+			if other != nil && other.ID != copy.ID {
+				logger.WithField("otherCopyId", other.ID).Warn("barcode already in use")
+				return errCopyBarcodeTaken
+			}
+
+			previous := ""
+			if copy.Barcode != nil {
+				previous = *copy.Barcode
+			}
+
+			change(enum.BarcodeChangeEvent, previous, *bookCopy.Barcode)
+			copy.Barcode = bookCopy.Barcode
+		}
+
+		if bookCopy.Location != nil {
+			change(enum.LocationMoveEvent, copy.Location, *bookCopy.Location)
+			copy.Location = *bookCopy.Location
+		}
+
+		if bookCopy.Condition != nil {
+			change(enum.ConditionNoteEvent, copy.Condition, *bookCopy.Condition)
+			copy.Condition = *bookCopy.Condition
+		}
+
+		if err := s.updateStatus(ctx, copy, to, bookCopy.Reason); err != nil {
+			return err
+		}
+
+		for i := range events {
+			if err := s.Record(ctx, &events[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			logger.WithError(err).Error("book copy not found")
-			return errNotFound
+		logger.WithError(err).Error("failed to update book copy")
+		return myerror.FromError(err)
+	}
+
+	logger.Info("book copy updated successfully")
+	return nil
+}
+
+// updateStatus saves copy, moving it to status to first unless to is
+// zero. It refuses to move a copy an open loan or a ready hold still
+// holds, but lets a stale loaned or reserved status be corrected.
+func (s *BookCopyServiceImpl) updateStatus(ctx context.Context, copy *model.BookCopy, to enum.CopyStatus, reason string) error {
+	logger := s.logWithCtx(ctx, "BookCopyService.updateStatus").
+		WithFields(log.Fields{
+			"copyId": copy.ID,
+			"from":   copy.Status,
+			"to":     to.String(),
+		})
+
+	if to == 0 || copy.Status == to.String() {
+		if err := s.copyRepo.Update(ctx, copy); err != nil {
+			logger.WithError(err).Error("failed to update book copy")
+			return errIntServer
 		}
+		return nil
+	}
+
+	loan, err := s.loanRepo.GetOpenByCopy(ctx, copy.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.WithError(err).Error("failed to get open loan by copy")
+		return errIntServer
+	}
+
+	if loan != nil {
+		logger.WithField("loanID", loan.ID).Warn("copy still has an open loan")
+		return errCopyOnLoan
+	}
+
+	hold, err := s.reservRepo.GetReadyByCopy(ctx, copy.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.WithError(err).Error("failed to get ready hold by copy")
+		return errIntServer
+	}
+
+	if hold != nil {
+		logger.WithField("reservationID", hold.ID).Warn("copy is held for a reservation")
+		return errCopyHeld
+	}
+
+	return s.Transition(ctx, copy, to, reason)
+}
+
+// Transition moves copy to status to when the state machine allows it,
+// saves the copy along with any other field the caller changed, and
+// records who made the change and why. Copies holding a status the
+// machine does not know may move anywhere, so bad data can be repaired.
+func (s *BookCopyServiceImpl) Transition(ctx context.Context, copy *model.BookCopy, to enum.CopyStatus, reason string) error {
+	logger := s.logWithCtx(ctx, "BookCopyService.Transition").
+		WithFields(log.Fields{
+			"copyId": copy.ID,
+			"from":   copy.Status,
+			"to":     to.String(),
+		})
+
+	from := copy.Status
+	if current, ok := enum.ParseCopyStatus(from); ok && current != to && !current.CanTransitionTo(to) {
+		logger.Warn("invalid copy status transition")
+		return myerror.NewBadRequestError(fmt.Sprintf("copy cannot go from %s to %s", from, to.String()))
+	}
+
+	copy.Status = to.String()
+
+	if err := s.copyRepo.Update(ctx, copy); err != nil {
 		logger.WithError(err).Error("failed to update book copy")
 		return errIntServer
 	}
 
-	logger.Info("book copy updated successfully")
+	if from == copy.Status {
+		return nil
+	}
+
+	err := s.Record(ctx, &model.ItemEvent{
+		BookCopyID: copy.ID,
+		Kind:       enum.StatusChangeEvent.String(),
+		FromStatus: from,
+		ToStatus:   copy.Status,
+		Reason:     reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("copy status changed")
 	return nil
 }
 
+// Record adds event to its copy's audit trail, attributing it to the
+// member and kiosk in ctx unless the caller set them.
+func (s *BookCopyServiceImpl) Record(ctx context.Context, event *model.ItemEvent) error {
+	logger := s.logWithCtx(ctx, "BookCopyService.Record").
+		WithFields(log.Fields{
+			"copyId": event.BookCopyID,
+			"kind":   event.Kind,
+		})
+
+	if event.ActorID == nil {
+		event.ActorID = helper.ActorIDFromContext(ctx)
+	}
+
+	if event.KioskID == nil {
+		event.KioskID = helper.KioskIDFromContext(ctx)
+	}
+
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		logger.WithError(err).Error("failed to record item event")
+		return errIntServer
+	}
+
+	return nil
+}
+
+func (s *BookCopyServiceImpl) GetHistory(ctx context.Context, bookID uuid.UUID, copyID uint) ([]dto.ItemEventResponse, error) {
+	logger := s.logWithCtx(ctx, "BookCopyService.GetHistory").
+		WithFields(log.Fields{
+			"bookId": bookID,
+			"copyId": copyID,
+		})
+
+	logger.Info("received get book copy history request")
+
+	copy, err := s.copyRepo.GetByID(ctx, copyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.WithError(err).Error("book copy not found")
+			return nil, errNotFound
+		}
+		logger.WithError(err).Error("failed to get book copy by ID")
+		return nil, errIntServer
+	}
+
+	if copy.BookID != bookID {
+		logger.WithField("copyBookId", copy.BookID).Warn("copy belongs to another book")
+		return nil, errNotFound
+	}
+
+	events, err := s.eventRepo.GetByCopy(ctx, copyID)
+	if err != nil {
+		logger.WithError(err).Error("failed to get item events")
+		return nil, errIntServer
+	}
+
+	response := []dto.ItemEventResponse{}
+	for _, v := range events {
+		response = append(response, dto.ToItemEventResponse(v))
+	}
+
+	logger.WithField("count", len(response)).Info("book copy history fetched successfully")
+	return response, nil
+}
+
 func (s *BookCopyServiceImpl) DeleteById(ctx context.Context, bookCopyId uint) error {
 	logger := s.logWithCtx(ctx, "BookCopyService.DeleteById").
 		WithField("copyId", bookCopyId)
 
 	logger.Info("received delete book copy request")
 
-	err := s.copyRepo.DeleteById(ctx, bookCopyId)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			logger.WithError(err).Error("book copy not found")
-			return errNotFound
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		copy, err := s.copyRepo.GetByIDForUpdate(ctx, bookCopyId)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock book copy by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errNotFound
+			default:
+				return errIntServer
+			}
 		}
-		logger.WithError(err).Error("failed to delete book copy")
-		return errIntServer
+
+		if err := s.copyRepo.DeleteById(ctx, bookCopyId); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				logger.WithError(err).Error("book copy not found")
+				return errNotFound
+			}
+			logger.WithError(err).Error("failed to delete book copy")
+			return errIntServer
+		}
+
+		return s.Record(ctx, &model.ItemEvent{
+			BookCopyID: copy.ID,
+			Kind:       enum.DeletedEvent.String(),
+			FromStatus: copy.Status,
+		})
+	})
+
+	if err != nil {
+		return myerror.FromError(err)
 	}
 
 	logger.Info("book copy deleted successfully")
@@ -130,11 +446,28 @@ func (s *BookCopyServiceImpl) GetByID(ctx context.Context, bookCopyId uint) (*dt
 		return nil, errIntServer
 	}
 
-	bookCopyRs := dto.BookCopyResponse{
-		ID:     rs.Model.ID,
-		Status: rs.Status,
-		BookID: rs.BookID,
+	bookCopyRs := dto.ToBookCopyResponse(*rs)
+	logger.Info("book copy fetched successfully")
+	return &bookCopyRs, nil
+}
+
+func (s *BookCopyServiceImpl) GetByBarcode(ctx context.Context, barcode string) (*dto.BookCopyResponse, error) {
+	logger := s.logWithCtx(ctx, "BookCopyService.GetByBarcode").
+		WithField("barcode", barcode)
+
+	logger.Info("received get book copy by barcode request")
+
+	rs, err := s.copyRepo.GetByBarcode(ctx, barcode)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.WithError(err).Error("book copy not found")
+			return nil, errNotFound
+		}
+		logger.WithError(err).Error("failed to get book copy by barcode")
+		return nil, errIntServer
 	}
+
+	bookCopyRs := dto.ToBookCopyResponse(*rs)
 	logger.Info("book copy fetched successfully")
 	return &bookCopyRs, nil
 }
@@ -155,17 +488,33 @@ func (s *BookCopyServiceImpl) GetAll(ctx context.Context) (*[]dto.BookCopyRespon
 
 	bookCopies := []dto.BookCopyResponse{}
 	for _, v := range *rs {
-		bookCopies = append(bookCopies, dto.BookCopyResponse{
-			ID:     v.Model.ID,
-			Status: v.Status,
-			BookID: v.BookID,
-		})
+		bookCopies = append(bookCopies, dto.ToBookCopyResponse(v))
 	}
 
 	logger.WithField("count", len(bookCopies)).Info("all book copies fetched successfully")
 	return &bookCopies, nil
 }
 
+// GetShelfSearch lists the copies staff should look for on the shelves.
+func (s *BookCopyServiceImpl) GetShelfSearch(ctx context.Context) ([]dto.BookCopyResponse, error) {
+	logger := s.logWithCtx(ctx, "BookCopyService.GetShelfSearch")
+	logger.Info("received get shelf search request")
+
+	rs, err := s.copyRepo.GetShelfSearch(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get copies flagged for shelf search")
+		return nil, errIntServer
+	}
+
+	bookCopies := []dto.BookCopyResponse{}
+	for _, v := range rs {
+		bookCopies = append(bookCopies, dto.ToBookCopyResponse(v))
+	}
+
+	logger.WithField("count", len(bookCopies)).Info("shelf search copies fetched successfully")
+	return bookCopies, nil
+}
+
 func (s *BookCopyServiceImpl) GetByCondition(ctx context.Context, bookCopy *dto.BookCopyRequest) (*[]dto.BookCopyResponse, error) {
 	logger := s.logWithCtx(ctx, "BookCopyService.GetByCondition").
 		WithFields(log.Fields{
@@ -192,11 +541,7 @@ func (s *BookCopyServiceImpl) GetByCondition(ctx context.Context, bookCopy *dto.
 
 	bookCopies := []dto.BookCopyResponse{}
 	for _, v := range *rs {
-		bookCopies = append(bookCopies, dto.BookCopyResponse{
-			ID:     v.Model.ID,
-			Status: v.Status,
-			BookID: v.BookID,
-		})
+		bookCopies = append(bookCopies, dto.ToBookCopyResponse(v))
 	}
 
 	logger.WithField("count", len(bookCopies)).Info("book copies fetched by condition successfully")
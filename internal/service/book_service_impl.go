@@ -24,14 +24,14 @@ var (
 type BookServiceImpl struct {
 	log      *logrus.Logger
 	repo     repository.BookRepository
-	copyrepo repository.BookCopyRepository
+	copyServ BookCopyService
 }
 
-func NewBookServiceImpl(log *logrus.Logger, repo repository.BookRepository, copy repository.BookCopyRepository) BookService {
+func NewBookServiceImpl(log *logrus.Logger, repo repository.BookRepository, copyServ BookCopyService) BookService {
 	return &BookServiceImpl{
 		log:      log,
 		repo:     repo,
-		copyrepo: copy,
+		copyServ: copyServ,
 	}
 }
 
@@ -74,6 +74,8 @@ func (s *BookServiceImpl) Create(ctx context.Context, data *dto.BookRequest) (*d
 		ISBN:            data.ISBN,
 		PublicationYear: data.PublicationYear,
 		Genre:           data.Genre,
+		Category:        data.Category,
+		WorkKey:         data.WorkKey,
 		Author:          authors,
 	}
 
@@ -94,9 +96,9 @@ func (s *BookServiceImpl) Create(ctx context.Context, data *dto.BookRequest) (*d
 	}
 
 	logger.Info("executing insert book copy query")
-	if err := s.copyrepo.Create(ctx, result.ID, "available", int(data.InitialCopy)); err != nil {
+	if err := s.copyServ.Create(ctx, result.ID, "available", int(data.InitialCopy)); err != nil {
 		logger.WithError(err).Error("failed to execute insert book copy query")
-		return nil, ErrIntServer
+		return nil, err
 	}
 
 	fetchedAuthors, err := s.repo.GetBooksAuthor(ctx, book)
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+type CalendarService interface {
+	logWithCtx(ctx context.Context, function string) *log.Entry
+	Load(ctx context.Context) (helper.Calendar, error)
+	Get(ctx context.Context) (*dto.CalendarResponse, error)
+	SetOpeningHours(ctx context.Context, weekday int, data *dto.OpeningHoursRequest) error
+	CreateClosure(ctx context.Context, data *dto.ClosureRequest) (*dto.ClosureResponse, error)
+	DeleteClosure(ctx context.Context, id uuid.UUID) error
+	ImportClosures(ctx context.Context, r io.Reader) (int, error)
+}
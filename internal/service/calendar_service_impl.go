@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	errClosureNotFound = myerror.NewNotFoundError("closure")
+	errInvalidWeekday  = myerror.NewBadRequestError("weekday must be between 0 (sunday) and 6 (saturday)")
+	errInvalidClosure  = myerror.NewBadRequestError("closure ends before it starts")
+	errInvalidICS      = myerror.NewBadRequestError("invalid icalendar file")
+)
+
+type CalendarServiceImpl struct {
+	log  *log.Logger
+	tx   repository.Transactor
+	repo repository.CalendarRepository
+}
+
+func NewCalendarService(log *log.Logger, tx repository.Transactor, repo repository.CalendarRepository) CalendarService {
+	return &CalendarServiceImpl{
+		log:  log,
+		tx:   tx,
+		repo: repo,
+	}
+}
+
+func (s *CalendarServiceImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *CalendarServiceImpl) Load(ctx context.Context) (helper.Calendar, error) {
+	logger := s.logWithCtx(ctx, "CalendarService.Load")
+
+	hours, err := s.repo.GetOpeningHours(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get opening hours")
+		return helper.Calendar{}, myerror.InternalServerErr
+	}
+
+	closures, err := s.repo.GetClosures(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get closures")
+		return helper.Calendar{}, myerror.InternalServerErr
+	}
+
+	return helper.Calendar{
+		Hours:    hours,
+		Closures: closures,
+	}, nil
+}
+
+func (s *CalendarServiceImpl) Get(ctx context.Context) (*dto.CalendarResponse, error) {
+	logger := s.logWithCtx(ctx, "CalendarService.Get")
+	logger.Info("received get calendar request")
+
+	calendar, err := s.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// weekdays without a stored row are open, list them too so the
+	// response always covers the whole week
+	week := make([]model.OpeningHours, 7)
+	for i := range week {
+		week[i].Weekday = i
+	}
+	for _, v := range calendar.Hours {
+		week[v.Weekday] = v
+	}
+
+	response := dto.CalendarResponse{
+		Hours:    []dto.OpeningHoursResponse{},
+		Closures: []dto.ClosureResponse{},
+	}
+	for _, v := range week {
+		response.Hours = append(response.Hours, dto.ToOpeningHoursResponse(v))
+	}
+	for _, v := range calendar.Closures {
+		response.Closures = append(response.Closures, dto.ToClosureResponse(v))
+	}
+
+	logger.WithField("closures", len(response.Closures)).Info("calendar fetched successfully")
+	return &response, nil
+}
+
+func (s *CalendarServiceImpl) SetOpeningHours(ctx context.Context, weekday int, data *dto.OpeningHoursRequest) error {
+	logger := s.logWithCtx(ctx, "CalendarService.SetOpeningHours").
+		WithFields(log.Fields{
+			"weekday": weekday,
+			"closed":  data.Closed,
+		})
+
+	logger.Info("received set opening hours request")
+
+	if weekday < 0 || weekday > 6 {
+		logger.Warn("invalid weekday")
+		return errInvalidWeekday
+	}
+
+	hours := model.OpeningHours{
+		Weekday:  weekday,
+		OpensAt:  data.OpensAt,
+		ClosesAt: data.ClosesAt,
+		Closed:   data.Closed,
+	}
+
+	if err := s.repo.SaveOpeningHours(ctx, &hours); err != nil {
+		logger.WithError(err).Error("failed to save opening hours")
+		return myerror.InternalServerErr
+	}
+
+	logger.Info("opening hours saved successfully")
+	return nil
+}
+
+func (s *CalendarServiceImpl) CreateClosure(ctx context.Context, data *dto.ClosureRequest) (*dto.ClosureResponse, error) {
+	logger := s.logWithCtx(ctx, "CalendarService.CreateClosure").
+		WithFields(log.Fields{
+			"startDate": data.StartDate,
+			"endDate":   data.EndDate,
+		})
+
+	logger.Info("received create closure request")
+
+	start, err := helper.ParseDate(data.StartDate)
+	if err != nil {
+		logger.WithError(err).Warn("invalid start date")
+		return nil, myerror.NewBadRequestError("invalid start date")
+	}
+
+	end, err := helper.ParseDate(data.EndDate)
+	if err != nil {
+		logger.WithError(err).Warn("invalid end date")
+		return nil, myerror.NewBadRequestError("invalid end date")
+	}
+
+	if end.Before(start) {
+		logger.Warn("closure ends before it starts")
+		return nil, errInvalidClosure
+	}
+
+	closure := model.Closure{
+		StartDate: start,
+		EndDate:   end,
+		Reason:    data.Reason,
+	}
+
+	if err := s.repo.CreateClosure(ctx, &closure); err != nil {
+		logger.WithError(err).Error("failed to create closure")
+		return nil, myerror.InternalServerErr
+	}
+
+	logger.WithField("closureID", closure.ID).Info("closure created successfully")
+	response := dto.ToClosureResponse(closure)
+	return &response, nil
+}
+
+func (s *CalendarServiceImpl) DeleteClosure(ctx context.Context, id uuid.UUID) error {
+	logger := s.logWithCtx(ctx, "CalendarService.DeleteClosure").
+		WithField("closureID", id)
+
+	logger.Info("received delete closure request")
+
+	if err := s.repo.DeleteClosure(ctx, id); err != nil {
+		logger.WithError(err).Error("failed to delete closure")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return errClosureNotFound
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("closure deleted successfully")
+	return nil
+}
+
+func (s *CalendarServiceImpl) ImportClosures(ctx context.Context, r io.Reader) (int, error) {
+	logger := s.logWithCtx(ctx, "CalendarService.ImportClosures")
+	logger.Info("received import closures request")
+
+	closures, err := helper.ParseICSClosures(r)
+	if err != nil {
+		logger.WithError(err).Warn("failed to parse icalendar data")
+		return 0, errInvalidICS
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range closures {
+			if err := s.repo.UpsertClosure(ctx, &closures[i]); err != nil {
+				logger.WithError(err).Error("failed to save imported closure")
+				return myerror.InternalServerErr
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("import transaction failed")
+		return 0, myerror.FromError(err)
+	}
+
+	logger.WithField("imported", len(closures)).Info("closures imported successfully")
+	return len(closures), nil
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+type CirculationPolicyService interface {
+	logWithCtx(ctx context.Context, function string) *log.Entry
+	Resolve(ctx context.Context, memberCategory string, itemCategory string, genre string) (helper.CirculationPolicy, error)
+	GetAll(ctx context.Context) ([]dto.CirculationPolicyResponse, error)
+	Create(ctx context.Context, data *dto.CirculationPolicyRequest) (*dto.CirculationPolicyResponse, error)
+	Update(ctx context.Context, id uint, data *dto.CirculationPolicyRequest) error
+	DeleteByID(ctx context.Context, id uint) error
+}
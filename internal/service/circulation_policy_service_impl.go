@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	errPolicyNotFound  = myerror.NewNotFoundError("circulation policy")
+	errPolicyDuplicate = myerror.NewDuplicateError("circulation policy")
+)
+
+type CirculationPolicyServiceImpl struct {
+	log  *log.Logger
+	repo repository.CirculationPolicyRepository
+}
+
+func NewCirculationPolicyService(log *log.Logger, repo repository.CirculationPolicyRepository) CirculationPolicyService {
+	return &CirculationPolicyServiceImpl{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *CirculationPolicyServiceImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *CirculationPolicyServiceImpl) Resolve(ctx context.Context, memberCategory string, itemCategory string, genre string) (helper.CirculationPolicy, error) {
+	logger := s.logWithCtx(ctx, "CirculationPolicyService.Resolve").
+		WithFields(log.Fields{
+			"memberCategory": memberCategory,
+			"itemCategory":   itemCategory,
+			"genre":          genre,
+		})
+
+	policies, err := s.repo.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get circulation policies")
+		return helper.CirculationPolicy{}, myerror.InternalServerErr
+	}
+
+	policy := helper.ResolveCirculationPolicy(policies, memberCategory, itemCategory, genre, helper.DefaultCirculationPolicy())
+
+	logger.WithFields(log.Fields{
+		"loanDays":    policy.LoanDays,
+		"maxRenewals": policy.MaxRenewals,
+		"maxItems":    policy.MaxItems,
+	}).Info("circulation policy resolved")
+	return policy, nil
+}
+
+func (s *CirculationPolicyServiceImpl) GetAll(ctx context.Context) ([]dto.CirculationPolicyResponse, error) {
+	logger := s.logWithCtx(ctx, "CirculationPolicyService.GetAll")
+	logger.Info("received get circulation policies request")
+
+	policies, err := s.repo.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get circulation policies from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	response := []dto.CirculationPolicyResponse{}
+	for _, v := range policies {
+		response = append(response, dto.ToCirculationPolicyResponse(v))
+	}
+
+	logger.WithField("count", len(response)).Info("circulation policies fetched successfully")
+	return response, nil
+}
+
+func (s *CirculationPolicyServiceImpl) Create(ctx context.Context, data *dto.CirculationPolicyRequest) (*dto.CirculationPolicyResponse, error) {
+	logger := s.logWithCtx(ctx, "CirculationPolicyService.Create").
+		WithFields(log.Fields{
+			"memberCategory": data.MemberCategory,
+			"itemCategory":   data.ItemCategory,
+			"genre":          data.Genre,
+		})
+
+	logger.Info("received create circulation policy request")
+
+	policy := model.CirculationPolicy{
+		MemberCategory: data.MemberCategory,
+		ItemCategory:   data.ItemCategory,
+		Genre:          data.Genre,
+		LoanDays:       data.LoanDays,
+		MaxRenewals:    data.MaxRenewals,
+		MaxItems:       data.MaxItems,
+		DailyFineRate:  data.DailyFineRate,
+		GraceDays:      data.GraceDays,
+		MaxFine:        data.MaxFine,
+	}
+
+	result, err := s.repo.Create(ctx, &policy)
+	if err != nil {
+		logger.WithError(err).Error("failed to create circulation policy in repository")
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, errPolicyDuplicate
+		}
+		return nil, myerror.InternalServerErr
+	}
+
+	logger.WithField("policyID", result.ID).Info("circulation policy created successfully")
+	response := dto.ToCirculationPolicyResponse(*result)
+	return &response, nil
+}
+
+func (s *CirculationPolicyServiceImpl) Update(ctx context.Context, id uint, data *dto.CirculationPolicyRequest) error {
+	logger := s.logWithCtx(ctx, "CirculationPolicyService.Update").
+		WithField("policyID", id)
+
+	logger.Info("received update circulation policy request")
+
+	policy := model.CirculationPolicy{
+		Model:          gorm.Model{ID: id},
+		MemberCategory: data.MemberCategory,
+		ItemCategory:   data.ItemCategory,
+		Genre:          data.Genre,
+		LoanDays:       data.LoanDays,
+		MaxRenewals:    data.MaxRenewals,
+		MaxItems:       data.MaxItems,
+		DailyFineRate:  data.DailyFineRate,
+		GraceDays:      data.GraceDays,
+		MaxFine:        data.MaxFine,
+	}
+
+	if err := s.repo.Update(ctx, &policy); err != nil {
+		logger.WithError(err).Error("failed to update circulation policy in repository")
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return errPolicyDuplicate
+		}
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return errPolicyNotFound
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("circulation policy updated successfully")
+	return nil
+}
+
+func (s *CirculationPolicyServiceImpl) DeleteByID(ctx context.Context, id uint) error {
+	logger := s.logWithCtx(ctx, "CirculationPolicyService.DeleteByID").
+		WithField("policyID", id)
+
+	logger.Info("received delete circulation policy request")
+
+	if err := s.repo.DeleteByID(ctx, id); err != nil {
+		logger.WithError(err).Error("failed to delete circulation policy in repository")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return errPolicyNotFound
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("circulation policy deleted successfully")
+	return nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+)
+
+type CirculationService interface {
+	StartSession(ctx context.Context, data *dto.PatronSessionRequest) (*dto.PatronSessionResponse, error)
+	StartKioskSession(ctx context.Context, data *dto.KioskSessionRequest) (*dto.PatronSessionResponse, error)
+	EndSession(ctx context.Context, id uuid.UUID) error
+	Scan(ctx context.Context, data *dto.ScanRequest) (*dto.ScanResponse, error)
+}
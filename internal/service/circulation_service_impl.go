@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	errSessionNotFound = myerror.NewNotFoundError("patron session")
+	errSessionExpired  = myerror.NewBadRequestError("patron session has ended")
+	errSessionNotOwned = myerror.NewForbiddenError("patron session belongs to another desk")
+	errSessionRequired = myerror.NewBadRequestError("start a patron session before checking items out")
+	errScanEmpty       = myerror.NewBadRequestError("barcode is required")
+	errScanMemberCard  = myerror.NewBadRequestError("member card scanned, start a patron session with it instead")
+
+	errKioskLogin       = myerror.NewUnauthorizedError("invalid card or PIN")
+	errKioskNoPIN       = myerror.NewBadRequestError("no PIN set, please ask at the desk")
+	errKioskPINLocked   = myerror.NewForbiddenError("too many wrong PINs, please ask at the desk")
+	errKioskAccount     = myerror.NewForbiddenError("account cannot borrow, please ask at the desk")
+	errKioskForeignLoan = myerror.NewForbiddenError("item is on loan to another member, please return it at the desk")
+)
+
+// CirculationServiceImpl drives the desk from barcode scans. Checkouts
+// and returns themselves are left to the loan service.
+type CirculationServiceImpl struct {
+	log         *log.Logger
+	sessionRepo repository.PatronSessionRepository
+	memberRepo  repository.MemberRepository
+	copyRepo    repository.BookCopyRepository
+	loanRepo    repository.LoanRepository
+	loanServ    LoanService
+}
+
+func NewCirculationService(log *log.Logger, sessionRepo repository.PatronSessionRepository, memberRepo repository.MemberRepository, copyRepo repository.BookCopyRepository, loanRepo repository.LoanRepository, loanServ LoanService) CirculationService {
+	return &CirculationServiceImpl{
+		log:         log,
+		sessionRepo: sessionRepo,
+		memberRepo:  memberRepo,
+		copyRepo:    copyRepo,
+		loanRepo:    loanRepo,
+		loanServ:    loanServ,
+	}
+}
+
+func (s *CirculationServiceImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+// StartSession opens a patron session for the member whose card was
+// scanned, owned by the staff member making the request.
+func (s *CirculationServiceImpl) StartSession(ctx context.Context, data *dto.PatronSessionRequest) (*dto.PatronSessionResponse, error) {
+	logger := s.logWithCtx(ctx, "CirculationService.StartSession").
+		WithField("memberBarcode", data.MemberBarcode)
+
+	logger.Info("received start patron session request")
+
+	barcode := strings.TrimSpace(data.MemberBarcode)
+	if barcode == "" {
+		return nil, errScanEmpty
+	}
+
+	member, err := s.memberRepo.GetByBarcode(ctx, barcode)
+	if err != nil {
+		logger.WithError(err).Error("failed to get member by barcode")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, myerror.NewNotFoundError("member")
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	session := model.PatronSession{
+		MemberID:   member.ID,
+		StaffID:    helper.ActorIDFromContext(ctx),
+		LastScanAt: time.Now(),
+	}
+
+	if err := s.sessionRepo.Create(ctx, &session); err != nil {
+		logger.WithError(err).Error("failed to create patron session")
+		return nil, myerror.InternalServerErr
+	}
+
+	logger.WithFields(log.Fields{
+		"sessionID": session.ID,
+		"memberID":  member.ID,
+	}).Info("patron session started")
+	response := dto.ToPatronSessionResponse(session, *member)
+	return &response, nil
+}
+
+// StartKioskSession signs a member in at the kiosk in ctx with their card
+// barcode and PIN. KIOSK_PIN_MAX_ATTEMPTS wrong PINs in a row lock the
+// PIN until it is set again.
+func (s *CirculationServiceImpl) StartKioskSession(ctx context.Context, data *dto.KioskSessionRequest) (*dto.PatronSessionResponse, error) {
+	kioskID := helper.KioskIDFromContext(ctx)
+	logger := s.logWithCtx(ctx, "CirculationService.StartKioskSession").
+		WithFields(log.Fields{
+			"kioskID": kioskID,
+			"barcode": data.Barcode,
+		})
+
+	logger.Info("received start kiosk session request")
+
+	if kioskID == nil {
+		logger.Warn("kiosk session requested outside a kiosk")
+		return nil, errKioskCredential
+	}
+
+	member, err := s.memberRepo.GetByBarcode(ctx, strings.TrimSpace(data.Barcode))
+	if err != nil {
+		logger.WithError(err).Warn("failed to get member by barcode")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, errKioskLogin
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	if member.PIN == "" {
+		logger.WithField("memberID", member.ID).Warn("member has no PIN")
+		return nil, errKioskNoPIN
+	}
+
+	maxAttempts := helper.GetEnvInt("KIOSK_PIN_MAX_ATTEMPTS", 5)
+	if member.PINFailures >= maxAttempts {
+		logger.WithField("memberID", member.ID).Warn("member PIN locked")
+		return nil, errKioskPINLocked
+	}
+
+	// failures are counted and cleared in the database, so parallel
+	// attempts cannot get past the lock between our read and write
+	if !helper.CheckPassword(member.PIN, data.PIN) {
+		failures, err := s.memberRepo.IncrementPINFailures(ctx, member.ID, maxAttempts)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				logger.WithField("memberID", member.ID).Warn("member PIN locked")
+				return nil, errKioskPINLocked
+			}
+			logger.WithError(err).Error("failed to record PIN failure")
+			return nil, myerror.InternalServerErr
+		}
+
+		logger.WithFields(log.Fields{
+			"memberID": member.ID,
+			"failures": failures,
+		}).Warn("wrong PIN at kiosk")
+
+		if failures >= maxAttempts {
+			return nil, errKioskPINLocked
+		}
+		return nil, errKioskLogin
+	}
+
+	if err := s.memberRepo.ResetPINFailures(ctx, member.ID, maxAttempts); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.WithField("memberID", member.ID).Warn("member PIN locked")
+			return nil, errKioskPINLocked
+		}
+		logger.WithError(err).Error("failed to clear PIN failures")
+		return nil, myerror.InternalServerErr
+	}
+
+	if member.AccountStatus != enum.ActiveAccount.String() {
+		logger.WithField("accountStatus", member.AccountStatus).Warn("inactive member at kiosk")
+		return nil, errKioskAccount
+	}
+
+	session := model.PatronSession{
+		MemberID:   member.ID,
+		KioskID:    kioskID,
+		LastScanAt: time.Now(),
+	}
+
+	if err := s.sessionRepo.Create(ctx, &session); err != nil {
+		logger.WithError(err).Error("failed to create patron session")
+		return nil, myerror.InternalServerErr
+	}
+
+	logger.WithFields(log.Fields{
+		"sessionID": session.ID,
+		"memberID":  member.ID,
+	}).Info("kiosk session started")
+	response := dto.ToPatronSessionResponse(session, *member)
+	return &response, nil
+}
+
+func (s *CirculationServiceImpl) EndSession(ctx context.Context, id uuid.UUID) error {
+	logger := s.logWithCtx(ctx, "CirculationService.EndSession").
+		WithField("sessionID", id)
+
+	logger.Info("received end patron session request")
+
+	if _, err := s.openSession(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.End(ctx, id, time.Now()); err != nil {
+		logger.WithError(err).Error("failed to end patron session")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return errSessionExpired
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("patron session ended")
+	return nil
+}
+
+// Scan checks an item in when it is out on loan, and otherwise checks it
+// out to the member of the given patron session. At a kiosk every scan
+// needs the session, acts as its member, only returns that member's loans
+// and cannot override checkout blocks.
+func (s *CirculationServiceImpl) Scan(ctx context.Context, data *dto.ScanRequest) (*dto.ScanResponse, error) {
+	kioskID := helper.KioskIDFromContext(ctx)
+	logger := s.logWithCtx(ctx, "CirculationService.Scan").
+		WithFields(log.Fields{
+			"barcode":   data.Barcode,
+			"sessionID": data.SessionID,
+			"kioskID":   kioskID,
+		})
+
+	logger.Info("received scan request")
+
+	barcode := strings.TrimSpace(data.Barcode)
+	if barcode == "" {
+		return nil, errScanEmpty
+	}
+
+	var session *model.PatronSession
+	if kioskID != nil {
+		if data.SessionID == nil {
+			logger.Warn("kiosk scan without a patron session")
+			return nil, errSessionRequired
+		}
+
+		var err error
+		if session, err = s.openSession(ctx, *data.SessionID); err != nil {
+			return nil, err
+		}
+
+		ctx = helper.WithMember(ctx, session.MemberID)
+		data.Override = false
+	}
+
+	copy, err := s.copyRepo.GetByBarcode(ctx, barcode)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			logger.WithError(err).Error("failed to get book copy by barcode")
+			return nil, myerror.InternalServerErr
+		}
+
+		if helper.ValidBarcode(helper.MemberBarcodePrefix(), barcode) {
+			logger.Warn("member card scanned as an item")
+			return nil, errScanMemberCard
+		}
+
+		logger.Warn("no copy with scanned barcode")
+		return nil, myerror.NewNotFoundError("book copy")
+	}
+
+	loan, err := s.loanRepo.GetOpenByCopy(ctx, copy.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.WithError(err).Error("failed to get open loan by copy")
+		return nil, myerror.InternalServerErr
+	}
+
+	if loan != nil {
+		if session != nil && kioskID != nil && loan.MemberID != session.MemberID {
+			logger.WithField("loanID", loan.ID).Warn("kiosk return of another member's loan")
+			return nil, errKioskForeignLoan
+		}
+
+		receipt, err := s.loanServ.Return(ctx, loan.ID, &dto.LoanReturnRequest{})
+		if err != nil {
+			return nil, err
+		}
+
+		if session != nil {
+			if err := s.sessionRepo.Touch(ctx, session.ID, time.Now()); err != nil {
+				logger.WithError(err).Warn("failed to touch patron session")
+			}
+		}
+
+		logger.WithField("loanID", loan.ID).Info("scan checked item in")
+		return &dto.ScanResponse{
+			Action:  enum.ScanCheckin.String(),
+			Receipt: receipt,
+		}, nil
+	}
+
+	if session == nil {
+		if data.SessionID == nil {
+			logger.Warn("checkout scan without a patron session")
+			return nil, errSessionRequired
+		}
+
+		if session, err = s.openSession(ctx, *data.SessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.loanServ.Create(ctx, &dto.LoanRequest{
+		MemberID:       session.MemberID,
+		BookCopyID:     copy.ID,
+		Override:       data.Override,
+		OverrideReason: data.OverrideReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Touch(ctx, session.ID, time.Now()); err != nil {
+		logger.WithError(err).Warn("failed to touch patron session")
+	}
+
+	logger.WithField("loanID", created.ID).Info("scan checked item out")
+	return &dto.ScanResponse{
+		Action: enum.ScanCheckout.String(),
+		Loan:   created,
+	}, nil
+}
+
+// openSession returns the session when it is still open, belongs to the
+// requesting staff member or kiosk, and has seen a scan within
+// CIRCULATION_SESSION_IDLE_MINUTES, or KIOSK_SESSION_IDLE_MINUTES at a
+// kiosk.
+func (s *CirculationServiceImpl) openSession(ctx context.Context, id uuid.UUID) (*model.PatronSession, error) {
+	logger := s.logWithCtx(ctx, "CirculationService.openSession").
+		WithField("sessionID", id)
+
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to get patron session")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, errSessionNotFound
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	idle := time.Duration(helper.GetEnvInt("CIRCULATION_SESSION_IDLE_MINUTES", 15)) * time.Minute
+	owner := session.StaffID
+	requester := helper.ActorIDFromContext(ctx)
+
+	if kioskID := helper.KioskIDFromContext(ctx); kioskID != nil {
+		idle = time.Duration(helper.GetEnvInt("KIOSK_SESSION_IDLE_MINUTES", 2)) * time.Minute
+		owner = session.KioskID
+		requester = kioskID
+	}
+
+	if owner == nil || requester == nil || *owner != *requester {
+		logger.WithFields(log.Fields{
+			"staffID": session.StaffID,
+			"kioskID": session.KioskID,
+		}).Warn("patron session owned by someone else")
+		return nil, errSessionNotOwned
+	}
+	if session.EndedAt != nil || time.Since(session.LastScanAt) > idle {
+		logger.Warn("patron session ended or idle")
+		return nil, errSessionExpired
+	}
+
+	return session, nil
+}
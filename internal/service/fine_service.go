@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+type FineService interface {
+	logWithCtx(ctx context.Context, function string) *log.Entry
+	Create(ctx context.Context, data *dto.FineRequest) (*dto.FineResponse, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.FineResponse, error)
+	GetByMember(ctx context.Context, memberID uuid.UUID) ([]dto.FineResponse, error)
+	GetAll(ctx context.Context, status string) ([]dto.FineResponse, error)
+	Pay(ctx context.Context, id uuid.UUID, staffID uuid.UUID, data *dto.FinePaymentRequest) (*dto.FineResponse, error)
+	Waive(ctx context.Context, id uuid.UUID, staffID uuid.UUID, data *dto.FineWaiveRequest) (*dto.FineResponse, error)
+	AssessOverdue(ctx context.Context, loanID uuid.UUID, asOf time.Time) (*dto.FineResponse, error)
+	AccrueOverdue(ctx context.Context) (int, error)
+	Reverse(ctx context.Context, loanID uuid.UUID, reason enum.FineReason, note string) (*dto.FineResponse, float64, error)
+	Outstanding(ctx context.Context, memberID uuid.UUID) (float64, error)
+}
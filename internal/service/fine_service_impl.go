@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	errFineNotFound = myerror.NewNotFoundError("fine")
+	errFineSettled  = myerror.NewBadRequestError("fine already settled")
+	errFineOverpaid = myerror.NewBadRequestError("payment exceeds outstanding amount")
+)
+
+type FineServiceImpl struct {
+	log        *log.Logger
+	repo       repository.FineRepository
+	memberRepo repository.MemberRepository
+	loanRepo   repository.LoanRepository
+	copyRepo   repository.BookCopyRepository
+	bookRepo   repository.BookRepository
+	policyServ CirculationPolicyService
+	calServ    CalendarService
+}
+
+func NewFineService(log *log.Logger, repo repository.FineRepository, memberRepo repository.MemberRepository, loanRepo repository.LoanRepository, copyRepo repository.BookCopyRepository, bookRepo repository.BookRepository, policyServ CirculationPolicyService, calServ CalendarService) FineService {
+	return &FineServiceImpl{
+		log:        log,
+		repo:       repo,
+		memberRepo: memberRepo,
+		loanRepo:   loanRepo,
+		copyRepo:   copyRepo,
+		bookRepo:   bookRepo,
+		policyServ: policyServ,
+		calServ:    calServ,
+	}
+}
+
+func (s *FineServiceImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *FineServiceImpl) Create(ctx context.Context, data *dto.FineRequest) (*dto.FineResponse, error) {
+	logger := s.logWithCtx(ctx, "FineService.Create").
+		WithFields(log.Fields{
+			"memberID": data.MemberID,
+			"loanID":   data.LoanID,
+			"amount":   data.Amount,
+		})
+
+	logger.Info("received create fine request")
+
+	if _, err := s.memberRepo.GetByID(ctx, data.MemberID); err != nil {
+		logger.WithError(err).Error("failed to get member by ID")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, myerror.NewNotFoundError("member")
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	fine := model.Fine{
+		MemberID: data.MemberID,
+		LoanID:   data.LoanID,
+		Amount:   data.Amount,
+		Reason:   data.Reason,
+		Status:   enum.UnpaidFine.String(),
+	}
+
+	result, err := s.repo.Create(ctx, &fine)
+	if err != nil {
+		logger.WithError(err).Error("failed to create fine in repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	logger.WithField("fineID", result.ID).Info("fine created successfully")
+	response := dto.ToFineResponse(*result)
+	return &response, nil
+}
+
+func (s *FineServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*dto.FineResponse, error) {
+	logger := s.logWithCtx(ctx, "FineService.GetByID").
+		WithField("fineID", id)
+
+	logger.Info("received get fine by ID request")
+
+	result, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to get fine from repository")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, errFineNotFound
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("fine fetched successfully")
+	response := dto.ToFineResponse(*result)
+	return &response, nil
+}
+
+func (s *FineServiceImpl) GetByMember(ctx context.Context, memberID uuid.UUID) ([]dto.FineResponse, error) {
+	logger := s.logWithCtx(ctx, "FineService.GetByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("received get member fines request")
+
+	result, err := s.repo.GetByMember(ctx, memberID)
+	if err != nil {
+		logger.WithError(err).Error("failed to get member fines from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	response := []dto.FineResponse{}
+	for _, v := range result {
+		response = append(response, dto.ToFineResponse(v))
+	}
+
+	logger.WithField("count", len(response)).Info("member fines fetched successfully")
+	return response, nil
+}
+
+// Outstanding returns what the member still owes across all open fines.
+func (s *FineServiceImpl) Outstanding(ctx context.Context, memberID uuid.UUID) (float64, error) {
+	logger := s.logWithCtx(ctx, "FineService.Outstanding").
+		WithField("memberID", memberID)
+
+	total, err := s.repo.SumOutstandingByMember(ctx, memberID)
+	if err != nil {
+		logger.WithError(err).Error("failed to sum outstanding fines")
+		return 0, myerror.InternalServerErr
+	}
+
+	return total, nil
+}
+
+func (s *FineServiceImpl) GetAll(ctx context.Context, status string) ([]dto.FineResponse, error) {
+	logger := s.logWithCtx(ctx, "FineService.GetAll").
+		WithField("status", status)
+
+	logger.Info("received get all fines request")
+
+	if _, ok := enum.ParseFineStatus(status); status != "" && !ok {
+		logger.Warn("invalid fine status filter")
+		return nil, myerror.NewBadRequestError("status invalid")
+	}
+
+	result, err := s.repo.GetAll(ctx, status)
+	if err != nil {
+		logger.WithError(err).Error("failed to get fines from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	response := []dto.FineResponse{}
+	for _, v := range result {
+		response = append(response, dto.ToFineResponse(v))
+	}
+
+	logger.WithField("count", len(response)).Info("all fines fetched successfully")
+	return response, nil
+}
+
+func (s *FineServiceImpl) Pay(ctx context.Context, id uuid.UUID, staffID uuid.UUID, data *dto.FinePaymentRequest) (*dto.FineResponse, error) {
+	logger := s.logWithCtx(ctx, "FineService.Pay").
+		WithFields(log.Fields{
+			"fineID":  id,
+			"staffID": staffID,
+			"amount":  data.Amount,
+		})
+
+	logger.Info("received fine payment request")
+
+	fine, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to get fine from repository")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, errFineNotFound
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	if fine.Status == enum.PaidFine.String() || fine.Status == enum.WaivedFine.String() {
+		logger.WithField("status", fine.Status).Warn("fine already settled")
+		return nil, errFineSettled
+	}
+
+	if data.Amount > fine.Amount-fine.AmountPaid+0.005 {
+		logger.WithField("outstanding", fine.Amount-fine.AmountPaid).Warn("payment exceeds outstanding amount")
+		return nil, errFineOverpaid
+	}
+
+	payment := model.FinePayment{
+		FineID:     id,
+		Amount:     data.Amount,
+		ReceivedBy: staffID,
+		PaidAt:     time.Now(),
+	}
+
+	if err := s.repo.AddPayment(ctx, &payment); err != nil {
+		logger.WithError(err).Error("failed to record fine payment in repository")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, errFineOverpaid
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	logger.WithField("paymentID", payment.ID).Info("fine payment recorded successfully")
+	return s.GetByID(ctx, id)
+}
+
+func (s *FineServiceImpl) Waive(ctx context.Context, id uuid.UUID, staffID uuid.UUID, data *dto.FineWaiveRequest) (*dto.FineResponse, error) {
+	logger := s.logWithCtx(ctx, "FineService.Waive").
+		WithFields(log.Fields{
+			"fineID":  id,
+			"staffID": staffID,
+		})
+
+	logger.Info("received waive fine request")
+
+	fine, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to get fine from repository")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, errFineNotFound
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	if fine.Status == enum.PaidFine.String() || fine.Status == enum.WaivedFine.String() {
+		logger.WithField("status", fine.Status).Warn("fine already settled")
+		return nil, errFineSettled
+	}
+
+	now := time.Now()
+	fine.WaiveReason = data.Reason
+	fine.WaivedBy = &staffID
+	fine.WaivedAt = &now
+
+	if err := s.repo.Waive(ctx, fine); err != nil {
+		logger.WithError(err).Error("failed to waive fine in repository")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, errFineSettled
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("fine waived successfully")
+	return s.GetByID(ctx, id)
+}
+
+func (s *FineServiceImpl) AssessOverdue(ctx context.Context, loanID uuid.UUID, asOf time.Time) (*dto.FineResponse, error) {
+	logger := s.logWithCtx(ctx, "FineService.AssessOverdue").
+		WithFields(log.Fields{
+			"loanID": loanID,
+			"asOf":   asOf,
+		})
+
+	logger.Info("received assess overdue fine request")
+
+	loan, err := s.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		logger.WithError(err).Error("failed to get loan by ID")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, myerror.NewNotFoundError("loan")
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	member, err := s.memberRepo.GetByID(ctx, loan.MemberID)
+	if err != nil {
+		logger.WithError(err).Error("failed to get member by ID")
+		return nil, myerror.InternalServerErr
+	}
+
+	copy, err := s.copyRepo.GetByID(ctx, loan.BookCopyID)
+	if err != nil {
+		logger.WithError(err).Error("failed to get book copy by ID")
+		return nil, myerror.InternalServerErr
+	}
+
+	book, err := s.bookRepo.GetByID(ctx, copy.BookID)
+	if err != nil {
+		logger.WithError(err).Error("failed to get book by ID")
+		return nil, myerror.InternalServerErr
+	}
+
+	policy, err := s.policyServ.Resolve(ctx, member.Category, book.Category, book.Genre)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calServ.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := policy.Fine
+	amount := helper.CalculateOverdueFine(rate, calendar.OpenDaysOverdue(loan.DueDate, asOf))
+
+	logger = logger.WithFields(log.Fields{
+		"dailyRate": rate.DailyRate,
+		"amount":    amount,
+	})
+
+	existing, err := s.repo.GetByLoanAndReason(ctx, loanID, enum.OverdueFineReason.String())
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.WithError(err).Error("failed to get existing overdue fine")
+		return nil, myerror.InternalServerErr
+	}
+
+	if existing == nil {
+		if amount <= 0 {
+			logger.Info("loan is not chargeable")
+			return nil, nil
+		}
+
+		fine := model.Fine{
+			MemberID: loan.MemberID,
+			LoanID:   &loan.ID,
+			Amount:   amount,
+			Reason:   enum.OverdueFineReason.String(),
+			Status:   enum.UnpaidFine.String(),
+		}
+
+		if _, err := s.repo.Create(ctx, &fine); err != nil {
+			logger.WithError(err).Error("failed to create overdue fine")
+			return nil, myerror.InternalServerErr
+		}
+
+		logger.WithField("fineID", fine.ID).Info("overdue fine created")
+		return s.GetByID(ctx, fine.ID)
+	}
+
+	if existing.Status != enum.WaivedFine.String() && amount > existing.Amount {
+		if err := s.repo.UpdateAmount(ctx, existing.ID, amount); err != nil {
+			logger.WithError(err).Error("failed to update overdue fine amount")
+			return nil, myerror.InternalServerErr
+		}
+
+		logger.WithField("fineID", existing.ID).Info("overdue fine updated")
+	}
+
+	// a backdated return can land before days the accrual job already
+	// charged; a fine nothing has been paid on follows it back down
+	if existing.Status == enum.UnpaidFine.String() && amount < existing.Amount {
+		if amount <= 0 {
+			now := time.Now()
+			existing.WaiveReason = "returned before the fine was due"
+			existing.WaivedAt = &now
+
+			if err := s.repo.Waive(ctx, existing); err != nil {
+				logger.WithError(err).Error("failed to waive overdue fine in repository")
+				return nil, myerror.InternalServerErr
+			}
+
+			logger.WithField("fineID", existing.ID).Info("overdue fine waived")
+		} else {
+			if err := s.repo.UpdateAmount(ctx, existing.ID, amount); err != nil {
+				logger.WithError(err).Error("failed to update overdue fine amount")
+				return nil, myerror.InternalServerErr
+			}
+
+			logger.WithField("fineID", existing.ID).Info("overdue fine reduced")
+		}
+	}
+
+	return s.GetByID(ctx, existing.ID)
+}
+
+func (s *FineServiceImpl) AccrueOverdue(ctx context.Context) (int, error) {
+	logger := s.logWithCtx(ctx, "FineService.AccrueOverdue")
+	logger.Info("accruing overdue fines")
+
+	now := time.Now()
+
+	loans, err := s.loanRepo.GetUnreturnedPastDue(ctx, now)
+	if err != nil {
+		logger.WithError(err).Error("failed to get loans past due")
+		return 0, myerror.InternalServerErr
+	}
+
+	accrued := 0
+	for _, v := range loans {
+		fine, err := s.AssessOverdue(ctx, v.ID, now)
+		if err != nil {
+			logger.WithError(err).WithField("loanID", v.ID).Error("failed to accrue overdue fine")
+			continue
+		}
+		if fine != nil {
+			accrued++
+		}
+	}
+
+	logger.WithFields(log.Fields{
+		"loans":   len(loans),
+		"accrued": accrued,
+	}).Info("overdue fines accrued")
+	return accrued, nil
+}
+
+// Reverse cancels a loan's charge for reason, e.g. a lost item fee once the
+// item turns up. Whatever is still owed is waived; anything already paid is
+// returned as the amount to refund. It is a no-op when there is no charge.
+func (s *FineServiceImpl) Reverse(ctx context.Context, loanID uuid.UUID, reason enum.FineReason, note string) (*dto.FineResponse, float64, error) {
+	logger := s.logWithCtx(ctx, "FineService.Reverse").
+		WithFields(log.Fields{
+			"loanID": loanID,
+			"reason": reason.String(),
+		})
+
+	logger.Info("received reverse fine request")
+
+	fine, err := s.repo.GetByLoanAndReason(ctx, loanID, reason.String())
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Info("no fine to reverse")
+			return nil, 0, nil
+		}
+		logger.WithError(err).Error("failed to get fine by loan and reason")
+		return nil, 0, myerror.InternalServerErr
+	}
+
+	if fine.Status == enum.WaivedFine.String() {
+		logger.WithField("fineID", fine.ID).Info("fine already waived")
+		response, err := s.GetByID(ctx, fine.ID)
+		return response, 0, err
+	}
+
+	if fine.Status != enum.PaidFine.String() {
+		now := time.Now()
+		fine.WaiveReason = note
+		fine.WaivedAt = &now
+
+		if err := s.repo.Waive(ctx, fine); err != nil {
+			logger.WithError(err).Error("failed to waive fine in repository")
+			return nil, 0, myerror.InternalServerErr
+		}
+	}
+
+	logger.WithFields(log.Fields{
+		"fineID":    fine.ID,
+		"refundDue": fine.AmountPaid,
+	}).Info("fine reversed")
+
+	response, err := s.GetByID(ctx, fine.ID)
+	return response, fine.AmountPaid, err
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+)
+
+type KioskService interface {
+	Create(ctx context.Context, data *dto.KioskRequest) (*dto.KioskResponse, error)
+	GetAll(ctx context.Context) ([]dto.KioskResponse, error)
+	Deactivate(ctx context.Context, id uuid.UUID) error
+	Verify(ctx context.Context, id uuid.UUID, key string) error
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var errKioskCredential = myerror.NewUnauthorizedError("invalid kiosk credential")
+
+type KioskServiceImpl struct {
+	log  *log.Logger
+	repo repository.KioskRepository
+}
+
+func NewKioskService(log *log.Logger, repo repository.KioskRepository) KioskService {
+	return &KioskServiceImpl{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *KioskServiceImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+// Create registers a kiosk and returns its key. The key is not stored and
+// cannot be shown again; a lost key means registering the kiosk anew.
+func (s *KioskServiceImpl) Create(ctx context.Context, data *dto.KioskRequest) (*dto.KioskResponse, error) {
+	logger := s.logWithCtx(ctx, "KioskService.Create").
+		WithField("name", data.Name)
+
+	logger.Info("received create kiosk request")
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		logger.WithError(err).Error("failed to generate kiosk key")
+		return nil, myerror.InternalServerErr
+	}
+	key := hex.EncodeToString(raw)
+
+	hash, err := helper.HashPassword(key)
+	if err != nil {
+		logger.WithError(err).Error("failed to hash kiosk key")
+		return nil, myerror.InternalServerErr
+	}
+
+	kiosk := model.Kiosk{
+		Name:     data.Name,
+		Location: data.Location,
+		KeyHash:  hash,
+		Active:   true,
+	}
+
+	if err := s.repo.Create(ctx, &kiosk); err != nil {
+		logger.WithError(err).Error("failed to create kiosk")
+		return nil, myerror.InternalServerErr
+	}
+
+	logger.WithField("kioskID", kiosk.ID).Info("kiosk registered")
+	response := dto.ToKioskResponse(kiosk)
+	response.Key = key
+	return &response, nil
+}
+
+func (s *KioskServiceImpl) GetAll(ctx context.Context) ([]dto.KioskResponse, error) {
+	logger := s.logWithCtx(ctx, "KioskService.GetAll")
+
+	logger.Info("received get all kiosks request")
+
+	kiosks, err := s.repo.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get kiosks")
+		return nil, myerror.InternalServerErr
+	}
+
+	response := []dto.KioskResponse{}
+	for _, v := range kiosks {
+		response = append(response, dto.ToKioskResponse(v))
+	}
+
+	logger.WithField("count", len(response)).Info("kiosks fetched successfully")
+	return response, nil
+}
+
+func (s *KioskServiceImpl) Deactivate(ctx context.Context, id uuid.UUID) error {
+	logger := s.logWithCtx(ctx, "KioskService.Deactivate").
+		WithField("kioskID", id)
+
+	logger.Info("received deactivate kiosk request")
+
+	if err := s.repo.Deactivate(ctx, id); err != nil {
+		logger.WithError(err).Error("failed to deactivate kiosk")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return myerror.NewNotFoundError("kiosk")
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("kiosk deactivated")
+	return nil
+}
+
+// Verify checks a kiosk credential and notes when the kiosk was last seen.
+func (s *KioskServiceImpl) Verify(ctx context.Context, id uuid.UUID, key string) error {
+	logger := s.logWithCtx(ctx, "KioskService.Verify").
+		WithField("kioskID", id)
+
+	kiosk, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get kiosk")
+		if err == gorm.ErrRecordNotFound {
+			return errKioskCredential
+		}
+		return myerror.InternalServerErr
+	}
+
+	if !kiosk.Active || !helper.CheckPassword(kiosk.KeyHash, key) {
+		logger.WithField("active", kiosk.Active).Warn("kiosk credential rejected")
+		return errKioskCredential
+	}
+
+	if err := s.repo.Touch(ctx, id, time.Now()); err != nil {
+		logger.WithError(err).Warn("failed to touch kiosk")
+	}
+
+	return nil
+}
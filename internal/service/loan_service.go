@@ -12,6 +12,17 @@ type LoanService interface {
 	logWithCtx(ctx context.Context, function string) *log.Entry
 	Create(ctx context.Context, data *dto.LoanRequest) (*dto.LoanResponse, error)
 	Update(ctx context.Context, id uuid.UUID, data *dto.LoanUpdateRequest) error
+	Renew(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, staff bool) (*dto.LoanResponse, error)
+	Return(ctx context.Context, id uuid.UUID, data *dto.LoanReturnRequest) (*dto.ReturnReceipt, error)
+	BatchReturn(ctx context.Context, data *dto.BatchReturnRequest) (*dto.BatchReturnResponse, error)
+	MarkOverdue(ctx context.Context) (int, error)
+	DeclareLost(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, staff bool) (*dto.LostItemReceipt, error)
+	DeclareLongOverdueLost(ctx context.Context) (int, error)
+	ClaimReturned(ctx context.Context, id uuid.UUID, data *dto.ClaimReturnedRequest) (*dto.LoanResponse, error)
+	ResolveClaim(ctx context.Context, id uuid.UUID, data *dto.ClaimResolveRequest) (*dto.ClaimResolveResponse, error)
+	Recall(ctx context.Context, reservationID uuid.UUID, requesterID *uuid.UUID) (*dto.RecallResponse, error)
+	RecallForWaiting(ctx context.Context) (int, error)
+	GetByMember(ctx context.Context, memberID uuid.UUID, returned bool) ([]dto.LoanResponse, error)
 	DeleteById(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*dto.LoanResponse, error)
 	GetAll(ctx context.Context) (*[]dto.LoanResponse, error)
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -18,19 +19,60 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	errLoanNotFound = myerror.NewNotFoundError("loan")
+	errLoanClosed   = myerror.NewBadRequestError("loan already returned")
+
+	errLoanRecalled = myerror.NewBadRequestError("loan has been recalled")
+	errNoRecallable = myerror.NewBadRequestError("no loan can be recalled for this reservation")
+	errNotWaiting   = myerror.NewBadRequestError("reservation is not waiting in a queue")
+
+	errLoanLost          = myerror.NewBadRequestError("loan already declared lost")
+	errDamageChargeValue = myerror.NewBadRequestError("damage charge must be greater than 0")
+	errReturnedAtValue   = myerror.NewBadRequestError("returned_at cannot be in the future")
+	errBatchReturnEmpty  = myerror.NewBadRequestError("no barcodes or copy IDs to return")
+	errBatchReturnSize   = myerror.NewBadRequestError("too many items in one batch return")
+
+	errLoanClaimed     = myerror.NewBadRequestError("loan is claimed returned")
+	errLoanNotClaimed  = myerror.NewBadRequestError("loan is not claimed returned")
+	errClaimResolution = myerror.NewBadRequestError("resolution must be found or lost")
+
+	errCheckoutBlocked = myerror.NewBadRequestError("checkout blocked")
+	errOverrideDenied  = myerror.NewForbiddenError("not allowed to override checkout blocks")
+	errOverrideReason  = myerror.NewBadRequestError("override_reason is required to override checkout blocks")
+)
+
 type LoanServiceImpl struct {
 	log        *log.Logger
 	loanRepo   repository.LoanRepository
 	memberRepo repository.MemberRepository
 	copyRepo   repository.BookCopyRepository
+	bookRepo   repository.BookRepository
+	reservRepo repository.ReservationRepository
+	reservServ ReservationService
+	fineServ   FineService
+	policyServ CirculationPolicyService
+	calServ    CalendarService
+	notifServ  NotificationService
+	copyServ   BookCopyService
+	tx         repository.Transactor
 }
 
-func NewLoanServiceImpl(log *log.Logger, loanRepo repository.LoanRepository, memberRepo repository.MemberRepository, copyRepo repository.BookCopyRepository) LoanService {
+func NewLoanServiceImpl(log *log.Logger, tx repository.Transactor, loanRepo repository.LoanRepository, memberRepo repository.MemberRepository, copyRepo repository.BookCopyRepository, bookRepo repository.BookRepository, reservRepo repository.ReservationRepository, reservServ ReservationService, fineServ FineService, policyServ CirculationPolicyService, calServ CalendarService, notifServ NotificationService, copyServ BookCopyService) LoanService {
 	return &LoanServiceImpl{
 		log:        log,
+		tx:         tx,
 		loanRepo:   loanRepo,
 		memberRepo: memberRepo,
 		copyRepo:   copyRepo,
+		bookRepo:   bookRepo,
+		reservRepo: reservRepo,
+		reservServ: reservServ,
+		fineServ:   fineServ,
+		policyServ: policyServ,
+		calServ:    calServ,
+		notifServ:  notifServ,
+		copyServ:   copyServ,
 	}
 }
 
@@ -55,64 +97,207 @@ func (s *LoanServiceImpl) Create(ctx context.Context, data *dto.LoanRequest) (*d
 
 	logger.Info("received create loan request")
 
-	member, err := s.memberRepo.GetByID(ctx, data.MemberID)
-	if err != nil {
-		logger.WithError(err).Error("failed to get member by ID")
-		switch err {
-		case gorm.ErrRecordNotFound:
-			return nil, myerror.NewNotFoundError("member")
-		default:
-			return nil, myerror.InternalServerErr
-		}
+	loan := model.Loan{
+		MemberID:   data.MemberID,
+		BookCopyID: data.BookCopyID,
+		LoanDate:   time.Now(),
+		Status:     enum.ActiveLoan.String(),
 	}
 
-	if member.AccountStatus == enum.SuspendedAccount.String() || member.AccountStatus != enum.ActiveAccount.String() {
-		logger.Warn("member account is not active or is suspended")
-		return nil, myerror.NewBadRequestError("account suspended")
-	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
 
-	result, err := s.copyRepo.GetByID(ctx, data.BookCopyID)
-	if err != nil {
-		logger.WithError(err).Error("failed to get book copy by ID")
-		switch err {
-		case gorm.ErrRecordNotFound:
-			return nil, myerror.NewNotFoundError("book copy")
+		// locking the member serialises concurrent checkouts so the
+		// max items check below cannot be raced past
+		member, err := s.memberRepo.GetByIDForUpdate(ctx, data.MemberID)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock member by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return myerror.NewNotFoundError("member")
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if member.AccountStatus == enum.SuspendedAccount.String() || member.AccountStatus != enum.ActiveAccount.String() {
+			logger.Warn("member account is not active or is suspended")
+			return myerror.NewBadRequestError("account suspended")
+		}
+
+		result, err := s.copyRepo.GetByIDForUpdate(ctx, data.BookCopyID)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock book copy by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return myerror.NewNotFoundError("book copy")
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		switch result.Status {
+		case enum.AvailableCopy.String():
+		case enum.ReservedCopy.String():
+			if err := s.reservServ.CollectHold(ctx, result.ID, member.ID); err != nil {
+				return err
+			}
 		default:
-			return nil, myerror.InternalServerErr
+			logger.Warn("chosen book copy is unavailable")
+			return myerror.NewBadRequestError("chosen copy unavailable")
 		}
+
+		book, err := s.bookRepo.GetByID(ctx, result.BookID)
+		if err != nil {
+			logger.WithError(err).Error("failed to get book by ID")
+			return myerror.InternalServerErr
+		}
+
+		policy, err := s.policyServ.Resolve(ctx, member.Category, book.Category, book.Genre)
+		if err != nil {
+			return err
+		}
+
+		blocks, err := s.checkoutBlocks(ctx, member.ID, policy)
+		if err != nil {
+			return err
+		}
+
+		if len(blocks) > 0 {
+			if !data.Override {
+				logger.WithField("blocks", len(blocks)).Warn("checkout blocked")
+				return errCheckoutBlocked.WithDetails(blocks)
+			}
+
+			if !helper.HasPermission(ctx, enum.PermCirculationOverride.String()) {
+				logger.Warn("checkout override without permission")
+				return errOverrideDenied
+			}
+
+			if strings.TrimSpace(data.OverrideReason) == "" {
+				logger.Warn("checkout override without justification")
+				return errOverrideReason
+			}
+		}
+
+		calendar, err := s.calServ.Load(ctx)
+		if err != nil {
+			return err
+		}
+
+		loan.DueDate = calendar.NextOpenDay(loan.LoanDate.Add(policy.LoanPeriod()))
+
+		if _, err := s.loanRepo.Create(ctx, &loan); err != nil {
+			logger.WithError(err).Error("failed to create loan in repository")
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				logger.WithError(err).Error("duplicate loan error")
+				return myerror.NewDuplicateError("loan")
+			}
+			return myerror.InternalServerErr
+		}
+
+		checkout := model.ItemEvent{
+			BookCopyID: result.ID,
+			LoanID:     &loan.ID,
+			Kind:       enum.CheckoutEvent.String(),
+			ToValue:    loan.DueDate.Format(time.DateOnly),
+		}
+
+		if len(blocks) > 0 {
+			reasons := make([]string, 0, len(blocks))
+			for _, v := range blocks {
+				reasons = append(reasons, v.Reason)
+			}
+
+			override := model.CheckoutOverride{
+				LoanID:        loan.ID,
+				MemberID:      member.ID,
+				OverriddenBy:  helper.MemberIDFromContext(ctx),
+				Blocks:        strings.Join(reasons, ","),
+				Justification: strings.TrimSpace(data.OverrideReason),
+			}
+
+			if err := s.loanRepo.CreateOverride(ctx, &override); err != nil {
+				logger.WithError(err).Error("failed to record checkout override")
+				return myerror.InternalServerErr
+			}
+
+			logger.WithField("blocks", override.Blocks).Warn("checkout blocks overridden")
+			checkout.Reason = "override: " + override.Justification
+		}
+
+		if err := s.copyServ.Record(ctx, &checkout); err != nil {
+			return err
+		}
+
+		logger.Info("updating copy status to loaned")
+		return s.copyServ.Transition(ctx, result, enum.LoanedCopy, "checkout")
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("checkout transaction failed")
+		return nil, myerror.FromError(err)
 	}
 
-	if result.Status != enum.AvailableCopy.String() {
-		logger.Warn("chosen book copy is unavailable")
-		return nil, myerror.NewBadRequestError("chosen copy unavailable")
+	logger.WithField("loanID", loan.ID).Info("loan created successfully")
+	response := dto.ToLoanResponse(loan)
+	return &response, nil
+}
+
+// checkoutBlocks lists every reason the member may not borrow under policy:
+// too many items out, any overdue loan, or more than
+// CHECKOUT_MAX_UNPAID_FINES owed.
+func (s *LoanServiceImpl) checkoutBlocks(ctx context.Context, memberID uuid.UUID, policy helper.CirculationPolicy) ([]dto.CheckoutBlock, error) {
+	logger := s.logWithCtx(ctx, "LoanService.checkoutBlocks").
+		WithField("memberID", memberID)
+
+	blocks := []dto.CheckoutBlock{}
+
+	open, err := s.loanRepo.CountOpenByMember(ctx, memberID)
+	if err != nil {
+		logger.WithError(err).Error("failed to count open loans")
+		return nil, myerror.InternalServerErr
 	}
 
-	loan := model.Loan{
-		MemberID:   data.MemberID,
-		BookCopyID: data.BookCopyID,
-		LoanDate:   time.Now(),
-		DueDate:    time.Now().Add(time.Hour * 24 * 7),
-		Status:     enum.ActiveLoan.String(),
+	if open >= int64(policy.MaxItems) {
+		blocks = append(blocks, dto.CheckoutBlock{
+			Reason:  enum.MaxItemsBlock.String(),
+			Message: "loan limit reached",
+			Limit:   float64(policy.MaxItems),
+			Actual:  float64(open),
+		})
 	}
 
-	query, err := s.loanRepo.Create(ctx, &loan)
+	overdue, err := s.loanRepo.CountOverdueByMember(ctx, memberID, time.Now())
 	if err != nil {
-		logger.WithError(err).Error("failed to create loan in repository")
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			logger.WithError(err).Error("duplicate loan error")
-			return nil, myerror.NewDuplicateError("loan")
-		}
+		logger.WithError(err).Error("failed to count overdue loans")
 		return nil, myerror.InternalServerErr
 	}
 
-	log.Info("updating copy status to loaned")
-	result.Status = enum.LoanedCopy.String()
-	_ = s.copyRepo.Update(ctx, result)
+	if overdue > 0 {
+		blocks = append(blocks, dto.CheckoutBlock{
+			Reason:  enum.OverdueBlock.String(),
+			Message: "member has overdue loans",
+			Actual:  float64(overdue),
+		})
+	}
 
-	logger.WithField("loanID", query.ID).Info("loan created successfully")
-	response := dto.ToLoanResponse(*query)
-	return &response, nil
+	owed, err := s.fineServ.Outstanding(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := helper.GetEnvFloat("CHECKOUT_MAX_UNPAID_FINES", 50000)
+	if owed > threshold {
+		blocks = append(blocks, dto.CheckoutBlock{
+			Reason:  enum.UnpaidFinesBlock.String(),
+			Message: "unpaid fines over limit",
+			Limit:   threshold,
+			Actual:  owed,
+		})
+	}
+
+	return blocks, nil
 }
 
 func (s *LoanServiceImpl) Update(ctx context.Context, id uuid.UUID, data *dto.LoanUpdateRequest) error {
@@ -121,57 +306,1168 @@ func (s *LoanServiceImpl) Update(ctx context.Context, id uuid.UUID, data *dto.Lo
 	logger.Info("received update loan request")
 
 	var status string
-	if strings.ToLower(data.Status) == enum.ReturnedLoan.String() {
-		status = enum.ReturnedLoan.String()
-	} else if strings.ToLower(data.Status) == enum.OverdueLoan.String() {
+	if strings.ToLower(data.Status) == enum.OverdueLoan.String() {
 		status = enum.OverdueLoan.String()
+	} else if strings.ToLower(data.Status) == enum.ReturnedLoan.String() {
+		return myerror.NewBadRequestError("use the return endpoint to check in a loan")
 	} else {
 		return myerror.NewBadRequestError("status invalid")
 	}
 
-	loan := model.Loan{
-		ID:     id,
-		Status: status,
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock loan by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if loan.ReturnDate != nil || loan.Status == enum.ReturnedLoan.String() {
+			logger.Warn("loan already returned")
+			return errLoanClosed
+		}
+
+		if loan.Status == enum.LostLoan.String() {
+			logger.Warn("lost loans cannot be marked overdue")
+			return errLoanLost
+		}
+
+		if loan.Status == enum.ClaimsReturnedLoan.String() {
+			logger.Warn("claimed returned loans cannot be marked overdue")
+			return errLoanClaimed
+		}
+
+		if loan.Status == status {
+			return nil
+		}
+
+		previous := loan.Status
+		loan.Status = status
+
+		if err := s.loanRepo.Update(ctx, loan); err != nil {
+			logger.WithError(err).Error("failed to update loan in repository")
+			return myerror.InternalServerErr
+		}
+
+		return s.copyServ.Record(ctx, &model.ItemEvent{
+			BookCopyID: loan.BookCopyID,
+			LoanID:     &loan.ID,
+			Kind:       enum.OverdueEvent.String(),
+			FromStatus: previous,
+			ToStatus:   status,
+		})
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("update loan transaction failed")
+		return myerror.FromError(err)
+	}
+
+	logger.Info("loan updated successfully")
+	return nil
+}
+
+func (s *LoanServiceImpl) Return(ctx context.Context, id uuid.UUID, data *dto.LoanReturnRequest) (*dto.ReturnReceipt, error) {
+	logger := s.logWithCtx(ctx, "LoanService.Return").
+		WithFields(log.Fields{
+			"loanID":  id,
+			"damaged": data.Damaged,
+		})
+
+	logger.Info("received return loan request")
+
+	if data.DamageCharge != nil && *data.DamageCharge <= 0 {
+		logger.WithField("damageCharge", *data.DamageCharge).Warn("invalid damage charge")
+		return nil, errDamageChargeValue
 	}
 
-	err := s.loanRepo.Update(ctx, &loan)
+	if data.ReturnedAt != nil && data.ReturnedAt.After(time.Now()) {
+		logger.WithField("returnedAt", *data.ReturnedAt).Warn("invalid returned at")
+		return nil, errReturnedAtValue
+	}
+
+	receipt := dto.ReturnReceipt{}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock loan by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if loan.ReturnDate != nil || loan.Status == enum.ReturnedLoan.String() {
+			logger.Warn("loan already returned")
+			return errLoanClosed
+		}
+
+		copy, err := s.copyRepo.GetByIDForUpdate(ctx, loan.BookCopyID)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock book copy by ID")
+			return myerror.InternalServerErr
+		}
+
+		if err := s.clearShelfSearch(ctx, copy); err != nil {
+			return err
+		}
+
+		// a backdated return never goes back further than the loan itself
+		returnedAt := time.Now()
+		if data.ReturnedAt != nil {
+			returnedAt = *data.ReturnedAt
+			if returnedAt.Before(loan.LoanDate) {
+				returnedAt = loan.LoanDate
+			}
+		}
+
+		// a lost item turning up stops the clock at the day it was
+		// declared lost, and its replacement charge is reversed
+		wasLost := loan.Status == enum.LostLoan.String()
+		assessAt := returnedAt
+		if wasLost && loan.LostAt != nil && loan.LostAt.Before(assessAt) {
+			assessAt = *loan.LostAt
+		}
+
+		// so does a claim that the item had already been returned
+		if loan.ClaimedAt != nil && loan.ClaimedAt.Before(assessAt) {
+			assessAt = *loan.ClaimedAt
+		}
+
+		loan.ReturnDate = &returnedAt
+		loan.Status = enum.ReturnedLoan.String()
+
+		if err := s.loanRepo.Update(ctx, loan); err != nil {
+			logger.WithError(err).Error("failed to update loan in repository")
+			return myerror.InternalServerErr
+		}
+
+		err = s.copyServ.Record(ctx, &model.ItemEvent{
+			BookCopyID: copy.ID,
+			LoanID:     &loan.ID,
+			Kind:       enum.ReturnEvent.String(),
+			FromValue:  loan.DueDate.Format(time.DateOnly),
+			ToValue:    returnedAt.Format(time.DateOnly),
+		})
+		if err != nil {
+			return err
+		}
+
+		if wasLost {
+			lostFine, refund, err := s.fineServ.Reverse(ctx, loan.ID, enum.LostFineReason, "item returned")
+			if err != nil {
+				return err
+			}
+
+			receipt.LostFine = lostFine
+			receipt.RefundDue = refund
+		}
+
+		if data.Damaged {
+			if data.ConditionNote != "" && data.ConditionNote != copy.Condition {
+				err := s.copyServ.Record(ctx, &model.ItemEvent{
+					BookCopyID: copy.ID,
+					LoanID:     &loan.ID,
+					Kind:       enum.ConditionNoteEvent.String(),
+					FromValue:  copy.Condition,
+					ToValue:    data.ConditionNote,
+					Reason:     "returned damaged",
+				})
+				if err != nil {
+					return err
+				}
+
+				copy.Condition = data.ConditionNote
+			}
+
+			if data.DamageCharge != nil {
+				damageFine, err := s.fineServ.Create(ctx, &dto.FineRequest{
+					MemberID: loan.MemberID,
+					LoanID:   &loan.ID,
+					Amount:   *data.DamageCharge,
+					Reason:   enum.DamageFineReason.String(),
+				})
+				if err != nil {
+					return err
+				}
+
+				receipt.DamageFine = damageFine
+			}
+
+			if err := s.copyServ.Transition(ctx, copy, enum.DamagedCopy, "returned damaged"); err != nil {
+				return err
+			}
+		} else {
+			hold, err := s.reservServ.HoldCopy(ctx, copy)
+			if err != nil {
+				return err
+			}
+
+			if hold != nil {
+				receipt.HoldReservationID = &hold.ID
+			} else if err := s.copyServ.Transition(ctx, copy, enum.AvailableCopy, "returned"); err != nil {
+				return err
+			}
+		}
+
+		fine, err := s.fineServ.AssessOverdue(ctx, loan.ID, assessAt)
+		if err != nil {
+			logger.WithError(err).Error("failed to assess overdue fine")
+			return err
+		}
+
+		receipt.LoanID = loan.ID
+		receipt.MemberID = loan.MemberID
+		receipt.BookCopyID = loan.BookCopyID
+		receipt.DueDate = loan.DueDate
+		receipt.ReturnDate = returnedAt
+		receipt.DaysOverdue = helper.DaysOverdue(loan.DueDate, assessAt)
+		receipt.Fine = fine
+		receipt.CopyStatus = copy.Status
+
+		return nil
+	})
+
 	if err != nil {
-		logger.WithError(err).Error("failed to update loan in repository")
+		logger.WithError(err).Error("return transaction failed")
+		return nil, myerror.FromError(err)
+	}
+
+	logger.WithFields(log.Fields{
+		"copyStatus":        receipt.CopyStatus,
+		"holdReservationID": receipt.HoldReservationID,
+	}).Info("loan returned successfully")
+	return &receipt, nil
+}
+
+// BatchReturn checks in every copy in data on its own, so one bad item
+// does not stop the rest, and reports what happened to each. Returns are
+// backdated to ReturnedAt, moved back to the last open day when the
+// library was closed then, so closure days are never charged.
+func (s *LoanServiceImpl) BatchReturn(ctx context.Context, data *dto.BatchReturnRequest) (*dto.BatchReturnResponse, error) {
+	logger := s.logWithCtx(ctx, "LoanService.BatchReturn").
+		WithFields(log.Fields{
+			"barcodes": len(data.Barcodes),
+			"copyIDs":  len(data.CopyIDs),
+		})
+
+	logger.Info("received batch return request")
+
+	size := len(data.Barcodes) + len(data.CopyIDs)
+	if size == 0 {
+		return nil, errBatchReturnEmpty
+	}
+
+	if size > helper.GetEnvInt("RETURN_BATCH_MAX_ITEMS", 1000) {
+		logger.WithField("size", size).Warn("batch return too large")
+		return nil, errBatchReturnSize
+	}
+
+	now := time.Now()
+	if data.ReturnedAt != nil && data.ReturnedAt.After(now) {
+		logger.WithField("returnedAt", *data.ReturnedAt).Warn("invalid returned at")
+		return nil, errReturnedAtValue
+	}
+
+	calendar, err := s.calServ.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedAt := now
+	if data.ReturnedAt != nil {
+		returnedAt = *data.ReturnedAt
+	}
+	returnedAt = calendar.PrevOpenDay(returnedAt)
+
+	response := dto.BatchReturnResponse{
+		ReturnedAt: returnedAt,
+		Summary:    map[string]int{},
+		Items:      make([]dto.BatchReturnItem, 0, size),
+	}
+
+	for _, v := range data.Barcodes {
+		item := dto.BatchReturnItem{Barcode: v}
+
+		copy, err := s.copyRepo.GetByBarcode(ctx, strings.TrimSpace(v))
 		switch err {
+		case nil:
+			item.BookCopyID = copy.ID
+			s.batchReturnCopy(ctx, copy.ID, returnedAt, &item)
 		case gorm.ErrRecordNotFound:
-			return myerror.NewNotFoundError("loan")
+			item.Outcome = enum.CheckinUnknown.String()
 		default:
+			logger.WithError(err).WithField("barcode", v).Error("failed to get book copy by barcode")
+			item.Outcome = enum.CheckinFailed.String()
+			item.Error = myerror.InternalServerErr.Status
+		}
+
+		response.Summary[item.Outcome]++
+		response.Items = append(response.Items, item)
+	}
+
+	for _, v := range data.CopyIDs {
+		item := dto.BatchReturnItem{BookCopyID: v}
+
+		_, err := s.copyRepo.GetByID(ctx, v)
+		switch err {
+		case nil:
+			s.batchReturnCopy(ctx, v, returnedAt, &item)
+		case gorm.ErrRecordNotFound:
+			item.Outcome = enum.CheckinUnknown.String()
+		default:
+			logger.WithError(err).WithField("copyID", v).Error("failed to get book copy by ID")
+			item.Outcome = enum.CheckinFailed.String()
+			item.Error = myerror.InternalServerErr.Status
+		}
+
+		response.Summary[item.Outcome]++
+		response.Items = append(response.Items, item)
+	}
+
+	logger.WithFields(log.Fields{
+		"returnedAt": returnedAt,
+		"summary":    response.Summary,
+	}).Info("batch return processed")
+	return &response, nil
+}
+
+// batchReturnCopy returns the open loan on copyID, if there is one, and
+// fills in item with the outcome.
+func (s *LoanServiceImpl) batchReturnCopy(ctx context.Context, copyID uint, returnedAt time.Time, item *dto.BatchReturnItem) {
+	logger := s.logWithCtx(ctx, "LoanService.batchReturnCopy").
+		WithField("copyID", copyID)
+
+	loan, err := s.loanRepo.GetOpenByCopy(ctx, copyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			item.Outcome = enum.CheckinNotOnLoan.String()
+			return
+		}
+
+		logger.WithError(err).Error("failed to get open loan by copy")
+		item.Outcome = enum.CheckinFailed.String()
+		item.Error = myerror.InternalServerErr.Status
+		return
+	}
+
+	item.LoanID = &loan.ID
+	item.MemberID = &loan.MemberID
+
+	receipt, err := s.Return(ctx, loan.ID, &dto.LoanReturnRequest{ReturnedAt: &returnedAt})
+	if err != nil {
+		if err == errLoanClosed {
+			item.Outcome = enum.CheckinNotOnLoan.String()
+			return
+		}
+
+		logger.WithError(err).WithField("loanID", loan.ID).Error("failed to return loan")
+		item.Outcome = enum.CheckinFailed.String()
+		item.Error = myerror.FromError(err).Status
+		return
+	}
+
+	item.Fine = receipt.Fine
+	item.HoldReservationID = receipt.HoldReservationID
+	item.Outcome = enum.CheckinReturned.String()
+	if receipt.HoldReservationID != nil {
+		item.Outcome = enum.CheckinHoldTrapped.String()
+	}
+}
+
+func (s *LoanServiceImpl) Renew(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, staff bool) (*dto.LoanResponse, error) {
+	logger := s.logWithCtx(ctx, "LoanService.Renew").
+		WithFields(log.Fields{
+			"loanID":      id,
+			"requesterID": requesterID,
+			"staff":       staff,
+		})
+
+	logger.Info("received renew loan request")
+
+	var renewed *model.Loan
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock loan by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if !staff && loan.MemberID != requesterID {
+			logger.Warn("member tried to renew someone else's loan")
+			return errLoanNotFound
+		}
+
+		if loan.ReturnDate != nil || loan.Status == enum.ReturnedLoan.String() {
+			logger.Warn("loan already returned")
+			return errLoanClosed
+		}
+
+		if loan.Status == enum.LostLoan.String() {
+			logger.Warn("lost loans cannot be renewed")
+			return errLoanLost
+		}
+
+		if loan.Status == enum.ClaimsReturnedLoan.String() {
+			logger.Warn("claimed returned loans cannot be renewed")
+			return errLoanClaimed
+		}
+
+		member, err := s.memberRepo.GetByID(ctx, loan.MemberID)
+		if err != nil {
+			logger.WithError(err).Error("failed to get member by ID")
 			return myerror.InternalServerErr
 		}
+
+		copy, err := s.copyRepo.GetByID(ctx, loan.BookCopyID)
+		if err != nil {
+			logger.WithError(err).Error("failed to get book copy by ID")
+			return myerror.InternalServerErr
+		}
+
+		book, err := s.bookRepo.GetByID(ctx, copy.BookID)
+		if err != nil {
+			logger.WithError(err).Error("failed to get book by ID")
+			return myerror.InternalServerErr
+		}
+
+		policy, err := s.policyServ.Resolve(ctx, member.Category, book.Category, book.Genre)
+		if err != nil {
+			return err
+		}
+
+		if loan.RecalledAt != nil {
+			logger.WithField("recalledAt", loan.RecalledAt).Warn("recalled loans cannot be renewed")
+			return errLoanRecalled
+		}
+
+		if loan.RenewalCount >= policy.MaxRenewals {
+			logger.WithField("renewalCount", loan.RenewalCount).Warn("renewal limit reached")
+			return myerror.NewBadRequestError("renewal limit reached")
+		}
+
+		now := time.Now()
+		if helper.DaysOverdue(loan.DueDate, now) > helper.GetEnvInt("LOAN_RENEW_OVERDUE_DAYS", 0) {
+			logger.WithField("dueDate", loan.DueDate).Warn("loan is too far overdue to renew")
+			return myerror.NewBadRequestError("loan is too far overdue to renew")
+		}
+
+		next, err := s.reservRepo.GetNextInQueue(ctx, helper.CopyQueueKeys(copy.ID, copy.BookID, book.WorkKey))
+		if err != nil && err != gorm.ErrRecordNotFound {
+			logger.WithError(err).Error("failed to get next reservation in queue")
+			return myerror.InternalServerErr
+		}
+
+		if next != nil {
+			logger.WithField("reservationID", next.ID).Warn("copy is wanted by a pending reservation")
+			return myerror.NewBadRequestError("title has pending reservations")
+		}
+
+		calendar, err := s.calServ.Load(ctx)
+		if err != nil {
+			return err
+		}
+
+		base := loan.DueDate
+		if now.After(base) {
+			base = now
+		}
+
+		renewal := model.LoanRenewal{
+			LoanID:          loan.ID,
+			RenewedBy:       requesterID,
+			PreviousDueDate: loan.DueDate,
+			NewDueDate:      calendar.NextOpenDay(base.Add(policy.LoanPeriod())),
+		}
+
+		loan.DueDate = renewal.NewDueDate
+		loan.RenewalCount++
+		loan.Status = enum.ActiveLoan.String()
+
+		if err := s.loanRepo.Update(ctx, loan); err != nil {
+			logger.WithError(err).Error("failed to update loan in repository")
+			return myerror.InternalServerErr
+		}
+
+		if err := s.loanRepo.CreateRenewal(ctx, &renewal); err != nil {
+			logger.WithError(err).Error("failed to record loan renewal")
+			return myerror.InternalServerErr
+		}
+
+		err = s.copyServ.Record(ctx, &model.ItemEvent{
+			BookCopyID: loan.BookCopyID,
+			LoanID:     &loan.ID,
+			Kind:       enum.RenewEvent.String(),
+			FromValue:  renewal.PreviousDueDate.Format(time.DateOnly),
+			ToValue:    renewal.NewDueDate.Format(time.DateOnly),
+		})
+		if err != nil {
+			return err
+		}
+
+		renewed = loan
+		return nil
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("renew transaction failed")
+		return nil, myerror.FromError(err)
 	}
 
-	log.Infof("updating copy status to %s", data.BookStatus)
-	copy := model.BookCopy{
-		Model:  gorm.Model{ID: data.BookCopyID},
-		Status: data.BookStatus,
+	logger.WithFields(log.Fields{
+		"dueDate":      renewed.DueDate,
+		"renewalCount": renewed.RenewalCount,
+	}).Info("loan renewed successfully")
+	response := dto.ToLoanResponse(*renewed)
+	return &response, nil
+}
+
+func (s *LoanServiceImpl) MarkOverdue(ctx context.Context) (int, error) {
+	logger := s.logWithCtx(ctx, "LoanService.MarkOverdue")
+	logger.Info("marking loans past due as overdue")
+
+	marked := 0
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		loans, err := s.loanRepo.MarkOverdue(ctx, time.Now())
+		if err != nil {
+			logger.WithError(err).Error("failed to mark overdue loans")
+			return myerror.InternalServerErr
+		}
+
+		for _, v := range loans {
+			err := s.copyServ.Record(ctx, &model.ItemEvent{
+				BookCopyID: v.BookCopyID,
+				LoanID:     &v.ID,
+				Kind:       enum.OverdueEvent.String(),
+				FromStatus: enum.ActiveLoan.String(),
+				ToStatus:   v.Status,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		marked = len(loans)
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
 	}
 
-	_ = s.copyRepo.Update(ctx, &copy)
+	logger.WithField("marked", marked).Info("overdue loans marked")
+	return marked, nil
+}
 
-	logger.Info("loan updated successfully")
+// DeclareLost closes an open loan as lost and charges the member for the
+// copy. Members may only declare their own loans lost.
+func (s *LoanServiceImpl) DeclareLost(ctx context.Context, id uuid.UUID, requesterID uuid.UUID, staff bool) (*dto.LostItemReceipt, error) {
+	logger := s.logWithCtx(ctx, "LoanService.DeclareLost").
+		WithFields(log.Fields{
+			"loanID":      id,
+			"requesterID": requesterID,
+			"staff":       staff,
+		})
+
+	logger.Info("received declare lost request")
+
+	var receipt *dto.LostItemReceipt
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock loan by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if !staff && loan.MemberID != requesterID {
+			logger.Warn("member tried to declare someone else's loan lost")
+			return errLoanNotFound
+		}
+
+		receipt, err = s.declareLost(ctx, loan)
+		return err
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("declare lost transaction failed")
+		return nil, myerror.FromError(err)
+	}
+
+	logger.WithField("copyStatus", receipt.CopyStatus).Info("loan declared lost successfully")
+	return receipt, nil
+}
+
+// DeclareLongOverdueLost declares loans lost once they are more than
+// LOST_AFTER_DAYS_OVERDUE days past due. A value of 0 turns it off.
+func (s *LoanServiceImpl) DeclareLongOverdueLost(ctx context.Context) (int, error) {
+	logger := s.logWithCtx(ctx, "LoanService.DeclareLongOverdueLost")
+
+	days := helper.GetEnvInt("LOST_AFTER_DAYS_OVERDUE", 60)
+	if days <= 0 {
+		logger.Debug("automatic lost declaration disabled")
+		return 0, nil
+	}
+
+	logger.Info("declaring long overdue loans lost")
+
+	loans, err := s.loanRepo.GetUnreturnedPastDue(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		logger.WithError(err).Error("failed to get long overdue loans")
+		return 0, myerror.InternalServerErr
+	}
+
+	declared := 0
+	for _, v := range loans {
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			loan, err := s.loanRepo.GetByIDForUpdate(ctx, v.ID)
+			if err != nil {
+				return err
+			}
+
+			_, err = s.declareLost(ctx, loan)
+			return err
+		})
+
+		if err == errLoanClosed || err == errLoanLost {
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).WithField("loanID", v.ID).Error("failed to declare loan lost")
+			continue
+		}
+		declared++
+	}
+
+	logger.WithFields(log.Fields{
+		"loans":    len(loans),
+		"declared": declared,
+	}).Info("long overdue loans declared lost")
+	return declared, nil
+}
+
+// declareLost freezes the overdue fine, marks the loan and its copy lost
+// and charges the copy's replacement cost plus LOST_PROCESSING_FEE. Copies
+// without a replacement cost are charged LOST_DEFAULT_REPLACEMENT_COST. It
+// expects the loan to be locked inside a transaction.
+func (s *LoanServiceImpl) declareLost(ctx context.Context, loan *model.Loan) (*dto.LostItemReceipt, error) {
+	logger := s.logWithCtx(ctx, "LoanService.declareLost").
+		WithField("loanID", loan.ID)
+
+	if loan.Status == enum.LostLoan.String() {
+		logger.Warn("loan already declared lost")
+		return nil, errLoanLost
+	}
+
+	if loan.ReturnDate != nil || loan.Status == enum.ReturnedLoan.String() {
+		logger.Warn("loan already returned")
+		return nil, errLoanClosed
+	}
+
+	copy, err := s.copyRepo.GetByIDForUpdate(ctx, loan.BookCopyID)
+	if err != nil {
+		logger.WithError(err).Error("failed to lock book copy by ID")
+		return nil, myerror.InternalServerErr
+	}
+
+	if err := s.clearShelfSearch(ctx, copy); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// overdue fines stopped growing when the member claimed the return
+	assessAt := now
+	if loan.ClaimedAt != nil {
+		assessAt = *loan.ClaimedAt
+	}
+
+	overdueFine, err := s.fineServ.AssessOverdue(ctx, loan.ID, assessAt)
+	if err != nil {
+		logger.WithError(err).Error("failed to assess overdue fine")
+		return nil, err
+	}
+
+	previous := loan.Status
+	loan.Status = enum.LostLoan.String()
+	loan.LostAt = &now
+
+	if err := s.loanRepo.Update(ctx, loan); err != nil {
+		logger.WithError(err).Error("failed to update loan in repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	err = s.copyServ.Record(ctx, &model.ItemEvent{
+		BookCopyID: copy.ID,
+		LoanID:     &loan.ID,
+		Kind:       enum.LostEvent.String(),
+		FromStatus: previous,
+		ToStatus:   loan.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.copyServ.Transition(ctx, copy, enum.LostCopy, "declared lost"); err != nil {
+		return nil, err
+	}
+
+	cost := copy.ReplacementCost
+	if cost <= 0 {
+		cost = helper.GetEnvFloat("LOST_DEFAULT_REPLACEMENT_COST", 0)
+	}
+	amount := cost + helper.GetEnvFloat("LOST_PROCESSING_FEE", 0)
+
+	receipt := dto.LostItemReceipt{
+		LoanID:      loan.ID,
+		MemberID:    loan.MemberID,
+		BookCopyID:  loan.BookCopyID,
+		LoanStatus:  loan.Status,
+		CopyStatus:  copy.Status,
+		OverdueFine: overdueFine,
+	}
+
+	if amount > 0 {
+		lostFine, err := s.fineServ.Create(ctx, &dto.FineRequest{
+			MemberID: loan.MemberID,
+			LoanID:   &loan.ID,
+			Amount:   amount,
+			Reason:   enum.LostFineReason.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		receipt.LostFine = lostFine
+	}
+
+	logger.WithFields(log.Fields{
+		"bookCopyID": copy.ID,
+		"charged":    amount,
+	}).Info("loan declared lost")
+	return &receipt, nil
+}
+
+// ClaimReturned records the member's claim that they already returned the
+// loan. Its overdue fine stops growing, the copy is flagged for a shelf
+// search and the claim counts against the member until staff resolve it.
+func (s *LoanServiceImpl) ClaimReturned(ctx context.Context, id uuid.UUID, data *dto.ClaimReturnedRequest) (*dto.LoanResponse, error) {
+	logger := s.logWithCtx(ctx, "LoanService.ClaimReturned").
+		WithField("loanID", id)
+
+	logger.Info("received claim returned request")
+
+	var claimed *model.Loan
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock loan by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		switch {
+		case loan.ReturnDate != nil || loan.Status == enum.ReturnedLoan.String():
+			logger.Warn("loan already returned")
+			return errLoanClosed
+		case loan.Status == enum.LostLoan.String():
+			logger.Warn("loan already declared lost")
+			return errLoanLost
+		case loan.Status == enum.ClaimsReturnedLoan.String():
+			logger.Warn("loan already claimed returned")
+			return errLoanClaimed
+		}
+
+		now := time.Now()
+
+		if _, err := s.fineServ.AssessOverdue(ctx, loan.ID, now); err != nil {
+			logger.WithError(err).Error("failed to assess overdue fine")
+			return err
+		}
+
+		previous := loan.Status
+		loan.Status = enum.ClaimsReturnedLoan.String()
+		loan.ClaimedAt = &now
+
+		if err := s.loanRepo.Update(ctx, loan); err != nil {
+			logger.WithError(err).Error("failed to update loan in repository")
+			return myerror.InternalServerErr
+		}
+
+		if err := s.copyRepo.SetShelfSearch(ctx, loan.BookCopyID, &now); err != nil {
+			logger.WithError(err).Error("failed to flag book copy for shelf search")
+			return myerror.InternalServerErr
+		}
+
+		updates := map[string]interface{}{"claim_count": gorm.Expr("claim_count + 1")}
+		if err := s.memberRepo.Update(ctx, loan.MemberID, &updates); err != nil {
+			logger.WithError(err).Error("failed to count member claim")
+			return myerror.InternalServerErr
+		}
+
+		claimed = loan
+
+		return s.copyServ.Record(ctx, &model.ItemEvent{
+			BookCopyID: loan.BookCopyID,
+			LoanID:     &loan.ID,
+			Kind:       enum.ClaimsReturnedEvent.String(),
+			FromStatus: previous,
+			ToStatus:   loan.Status,
+			Reason:     data.Note,
+		})
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("claim returned transaction failed")
+		return nil, myerror.FromError(err)
+	}
+
+	logger.WithField("memberID", claimed.MemberID).Info("loan claimed returned")
+	response := dto.ToLoanResponse(*claimed)
+	return &response, nil
+}
+
+// ResolveClaim settles a claims-returned loan. A copy that was found closes
+// the loan as returned on the day of the claim; otherwise the loan is
+// declared lost and the member billed for the copy.
+func (s *LoanServiceImpl) ResolveClaim(ctx context.Context, id uuid.UUID, data *dto.ClaimResolveRequest) (*dto.ClaimResolveResponse, error) {
+	logger := s.logWithCtx(ctx, "LoanService.ResolveClaim").
+		WithFields(log.Fields{
+			"loanID":     id,
+			"resolution": data.Resolution,
+		})
+
+	logger.Info("received resolve claim request")
+
+	resolution, ok := enum.ParseClaimResolution(strings.ToLower(data.Resolution))
+	if !ok {
+		return nil, errClaimResolution
+	}
+
+	response := dto.ClaimResolveResponse{Resolution: resolution.String()}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock loan by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if loan.Status != enum.ClaimsReturnedLoan.String() || loan.ReturnDate != nil {
+			logger.WithField("status", loan.Status).Warn("loan is not claimed returned")
+			return errLoanNotClaimed
+		}
+
+		err = s.copyServ.Record(ctx, &model.ItemEvent{
+			BookCopyID: loan.BookCopyID,
+			LoanID:     &loan.ID,
+			Kind:       enum.ClaimResolvedEvent.String(),
+			ToValue:    resolution.String(),
+			Reason:     data.Note,
+		})
+		if err != nil {
+			return err
+		}
+
+		switch resolution {
+		case enum.ClaimFound:
+			response.Receipt, err = s.Return(ctx, loan.ID, &dto.LoanReturnRequest{ReturnedAt: loan.ClaimedAt})
+		case enum.ClaimLost:
+			response.LostReceipt, err = s.declareLost(ctx, loan)
+		}
+		return err
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("resolve claim transaction failed")
+		return nil, myerror.FromError(err)
+	}
+
+	logger.Info("claim resolved")
+	return &response, nil
+}
+
+// clearShelfSearch drops the copy's shelf search flag, if it has one, now
+// that the copy has been accounted for.
+func (s *LoanServiceImpl) clearShelfSearch(ctx context.Context, copy *model.BookCopy) error {
+	if copy.ShelfSearchAt == nil {
+		return nil
+	}
+
+	if err := s.copyRepo.SetShelfSearch(ctx, copy.ID, nil); err != nil {
+		s.logWithCtx(ctx, "LoanService.clearShelfSearch").
+			WithField("copyID", copy.ID).
+			WithError(err).Error("failed to clear shelf search")
+		return myerror.InternalServerErr
+	}
+
+	copy.ShelfSearchAt = nil
 	return nil
 }
 
+// Recall pulls the due date of the longest-outstanding loan that could
+// fill a waiting reservation forward to RECALL_MIN_REMAINING_DAYS from
+// now, and tells the borrower. requesterID is nil for the recall job.
+func (s *LoanServiceImpl) Recall(ctx context.Context, reservationID uuid.UUID, requesterID *uuid.UUID) (*dto.RecallResponse, error) {
+	logger := s.logWithCtx(ctx, "LoanService.Recall").
+		WithFields(log.Fields{
+			"reservationID": reservationID,
+			"requesterID":   requesterID,
+		})
+
+	logger.Info("received recall request")
+
+	var recall *model.Recall
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		reservation, err := s.reservRepo.GetByID(ctx, reservationID)
+		if err != nil {
+			logger.WithError(err).Error("failed to get reservation by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return myerror.NewNotFoundError("reservation")
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if reservation.Status != enum.PendingReserv.String() {
+			logger.WithField("status", reservation.Status).Warn("reservation is not pending")
+			return errNotWaiting
+		}
+
+		recall, err = s.recallFor(ctx, reservation, requesterID)
+		return err
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("recall transaction failed")
+		return nil, myerror.FromError(err)
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":     recall.LoanID,
+		"newDueDate": recall.NewDueDate,
+	}).Info("loan recalled successfully")
+	response := dto.ToRecallResponse(*recall)
+	return &response, nil
+}
+
+// RecallForWaiting recalls a loan for every reservation that has waited
+// longer than RECALL_AFTER_DAYS without one. A value of 0 turns it off.
+func (s *LoanServiceImpl) RecallForWaiting(ctx context.Context) (int, error) {
+	logger := s.logWithCtx(ctx, "LoanService.RecallForWaiting")
+
+	days := helper.GetEnvInt("RECALL_AFTER_DAYS", 14)
+	if days <= 0 {
+		logger.Debug("automatic recall disabled")
+		return 0, nil
+	}
+
+	logger.Info("recalling loans for long waiting reservations")
+
+	waiting, err := s.reservRepo.GetAwaitingRecall(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		logger.WithError(err).Error("failed to get reservations awaiting recall")
+		return 0, myerror.InternalServerErr
+	}
+
+	recalled := 0
+	for _, v := range waiting {
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := s.recallFor(ctx, &v, nil)
+			return err
+		})
+
+		if err == errNoRecallable {
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).WithField("reservationID", v.ID).Error("failed to recall loan")
+			continue
+		}
+		recalled++
+	}
+
+	logger.WithFields(log.Fields{
+		"waiting":  len(waiting),
+		"recalled": recalled,
+	}).Info("loans recalled for waiting reservations")
+	return recalled, nil
+}
+
+// recallFor shortens the loan picked for reservation, records the recall
+// and notifies the borrower. It must run inside a transaction.
+func (s *LoanServiceImpl) recallFor(ctx context.Context, reservation *model.Reservation, requesterID *uuid.UUID) (*model.Recall, error) {
+	logger := s.logWithCtx(ctx, "LoanService.recallFor").
+		WithFields(log.Fields{
+			"reservationID": reservation.ID,
+			"queueKey":      reservation.QueueKey,
+		})
+
+	calendar, err := s.calServ.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	newDue := calendar.NextOpenDay(now.AddDate(0, 0, helper.GetEnvInt("RECALL_MIN_REMAINING_DAYS", 7)))
+
+	loan, err := s.loanRepo.GetRecallable(ctx, reservation.QueueKey, newDue)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Info("no loan eligible for recall")
+			return nil, errNoRecallable
+		}
+		logger.WithError(err).Error("failed to get recallable loan")
+		return nil, myerror.InternalServerErr
+	}
+
+	recall := model.Recall{
+		LoanID:          loan.ID,
+		ReservationID:   reservation.ID,
+		RecalledBy:      requesterID,
+		PreviousDueDate: loan.DueDate,
+		NewDueDate:      newDue,
+	}
+
+	loan.DueDate = newDue
+	loan.RecalledAt = &now
+
+	if err := s.loanRepo.Update(ctx, loan); err != nil {
+		logger.WithError(err).Error("failed to update loan in repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	if err := s.loanRepo.CreateRecall(ctx, &recall); err != nil {
+		logger.WithError(err).Error("failed to record recall")
+		return nil, myerror.InternalServerErr
+	}
+
+	err = s.copyServ.Record(ctx, &model.ItemEvent{
+		BookCopyID: loan.BookCopyID,
+		LoanID:     &loan.ID,
+		Kind:       enum.RecallEvent.String(),
+		FromValue:  recall.PreviousDueDate.Format(time.DateOnly),
+		ToValue:    newDue.Format(time.DateOnly),
+		ActorID:    requesterID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Copy %d is needed by another member and has been recalled. Please return it by %s.",
+		loan.BookCopyID, newDue.Format("2006-01-02"))
+	if err := s.notifServ.Notify(ctx, loan.MemberID, enum.RecallNotice, message); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(log.Fields{
+		"loanID":          loan.ID,
+		"previousDueDate": recall.PreviousDueDate,
+		"newDueDate":      newDue,
+	}).Info("loan recalled")
+	return &recall, nil
+}
+
 func (s *LoanServiceImpl) DeleteById(ctx context.Context, id uuid.UUID) error {
 	logger := s.logWithCtx(ctx, "LoanService.DeleteById").
 		WithField("loanID", id)
 
 	logger.Info("received delete loan request")
 
-	err := s.loanRepo.DeleteByID(ctx, id)
-	if err != nil {
-		logger.WithError(err).Error("failed to delete loan in repository")
-		switch err {
-		case gorm.ErrRecordNotFound:
-			return myerror.NewNotFoundError("loan")
-		default:
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock loan by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if err := s.loanRepo.DeleteByID(ctx, id); err != nil {
+			logger.WithError(err).Error("failed to delete loan in repository")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return errLoanNotFound
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		err = s.copyServ.Record(ctx, &model.ItemEvent{
+			BookCopyID: loan.BookCopyID,
+			LoanID:     &loan.ID,
+			Kind:       enum.DeletedEvent.String(),
+			FromStatus: loan.Status,
+		})
+		if err != nil {
+			return err
+		}
+
+		if loan.ReturnDate != nil {
+			return nil
+		}
+
+		// nothing returns a copy whose open loan is gone, so it goes back
+		// to the shelf or the next hold here, as a return would
+		copy, err := s.copyRepo.GetByIDForUpdate(ctx, loan.BookCopyID)
+		if err != nil {
+			logger.WithError(err).Error("failed to lock book copy by ID")
 			return myerror.InternalServerErr
 		}
+
+		if copy.Status != enum.LoanedCopy.String() {
+			return nil
+		}
+
+		hold, err := s.reservServ.HoldCopy(ctx, copy)
+		if err != nil {
+			return err
+		}
+
+		if hold != nil {
+			return nil
+		}
+
+		return s.copyServ.Transition(ctx, copy, enum.AvailableCopy, "loan deleted")
+	})
+
+	if err != nil {
+		return myerror.FromError(err)
 	}
 
 	logger.Info("loan deleted successfully")
@@ -222,3 +1518,27 @@ func (s *LoanServiceImpl) GetAll(ctx context.Context) (*[]dto.LoanResponse, erro
 	logger.WithField("count", len(responses)).Info("all loans fetched successfully")
 	return &responses, nil
 }
+
+func (s *LoanServiceImpl) GetByMember(ctx context.Context, memberID uuid.UUID, returned bool) ([]dto.LoanResponse, error) {
+	logger := s.logWithCtx(ctx, "LoanService.GetByMember").
+		WithFields(log.Fields{
+			"memberID": memberID,
+			"returned": returned,
+		})
+
+	logger.Info("received get member loans request")
+
+	result, err := s.loanRepo.GetByMember(ctx, memberID, returned)
+	if err != nil {
+		logger.WithError(err).Error("failed to get member loans from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	responses := []dto.LoanResponse{}
+	for _, v := range result {
+		responses = append(responses, dto.ToLoanResponse(v))
+	}
+
+	logger.WithField("count", len(responses)).Info("member loans fetched successfully")
+	return responses, nil
+}
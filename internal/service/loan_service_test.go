@@ -0,0 +1,70 @@
+package service
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+)
+
+func TestCreateLoanConcurrentCheckoutOfOneCopy(t *testing.T) {
+	s := newTestServices(t)
+
+	const attempts = 10
+
+	members := s.newMembers(t, attempts)
+	_, copies := s.newBook(t, 1, enum.AvailableCopy)
+	copy := copies[0]
+
+	start := make(chan struct{})
+	errs := make([]error, attempts)
+
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, errs[i] = s.loan.Create(testContext(), &dto.LoanRequest{
+				MemberID:   member.ID,
+				BookCopyID: copy.ID,
+			})
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+
+		myErr, ok := err.(myerror.MyError)
+		if !ok || myErr.Code >= http.StatusInternalServerError {
+			t.Errorf("checkout failed with %v, want a client error", err)
+		}
+	}
+
+	if succeeded != 1 {
+		t.Errorf("%d checkouts succeeded, want 1", succeeded)
+	}
+
+	var loans int64
+	s.db.Model(&model.Loan{}).Where("book_copy_id = ?", copy.ID).Count(&loans)
+	if loans != 1 {
+		t.Errorf("%d loans created for the copy, want 1", loans)
+	}
+
+	s.db.First(&copy, copy.ID)
+	if copy.Status != enum.LoanedCopy.String() {
+		t.Errorf("copy status = %s, want %s", copy.Status, enum.LoanedCopy)
+	}
+}
@@ -13,5 +13,8 @@ type MemberService interface {
 	UpdateMember(ctx context.Context, data *dto.MemberUpdateRequest) error
 	GetMemberByID(ctx context.Context, id uuid.UUID) (*dto.MemberResponse, error)
 	GetMemberByEmail(ctx context.Context, email string) (*dto.MemberResponse, error)
+	GetMemberByBarcode(ctx context.Context, barcode string) (*dto.MemberResponse, error)
 	DeleteMemberByID(ctx context.Context, id uuid.UUID) error
+	ManageMember(ctx context.Context, id uuid.UUID, data *dto.MemberManageRequest) error
+	SetPIN(ctx context.Context, id uuid.UUID, pin string) error
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nanoLeinz/librarium/internal/enum"
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/model"
 	"github.com/nanoLeinz/librarium/internal/model/dto"
@@ -33,7 +34,7 @@ func (s MemberServiceImpl) GetAllMembers(ctx context.Context) ([]dto.MemberRespo
 	result, err := s.repo.GetAll(ctx)
 	if err != nil {
 		s.log.WithField("function", "GetAllMembers").WithError(err).Error("Failed to fetch members from repository")
-		return nil, err
+		return nil, myerror.InternalServerErr
 	}
 
 	members := make([]dto.MemberResponse, 0, len(*result))
@@ -64,8 +65,11 @@ func (s MemberServiceImpl) CreateMember(ctx context.Context, data *dto.MemberCre
 		return nil, errors.New("failed hashing password")
 	}
 
+	barcode := helper.NewBarcode(helper.MemberBarcodePrefix())
+
 	user := model.Member{
 		Email:         data.Email,
+		Barcode:       &barcode,
 		Password:      hashedpass,
 		FullName:      data.FullName,
 		AccountStatus: data.AccountStatus,
@@ -203,6 +207,37 @@ func (s MemberServiceImpl) GetMemberByEmail(ctx context.Context, email string) (
 	return &result, nil
 }
 
+func (s MemberServiceImpl) GetMemberByBarcode(ctx context.Context, barcode string) (*dto.MemberResponse, error) {
+	s.log.WithFields(logrus.Fields{
+		"function": "GetMemberByBarcode",
+		"barcode":  barcode,
+	}).Info("Attempting to fetch member by barcode")
+
+	data, err := s.repo.GetByBarcode(ctx, barcode)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "GetMemberByBarcode",
+			"barcode":  barcode,
+		}).WithError(err).Error("Failed to get member from repository")
+
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, myerror.NewNotFoundError("member")
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	result := dto.ToMemberResponse(*data)
+	s.log.WithFields(logrus.Fields{
+		"function": "GetMemberByBarcode",
+		"barcode":  barcode,
+		"memberID": result.ID,
+	}).Info("Successfully fetched member by barcode")
+
+	return &result, nil
+}
+
 func (s MemberServiceImpl) DeleteMemberByID(ctx context.Context, id uuid.UUID) error {
 	s.log.WithFields(logrus.Fields{
 		"function": "DeleteMemberByID",
@@ -229,3 +264,124 @@ func (s MemberServiceImpl) DeleteMemberByID(ctx context.Context, id uuid.UUID) e
 	}).Info("Successfully deleted member")
 	return nil
 }
+
+func (s MemberServiceImpl) ManageMember(ctx context.Context, id uuid.UUID, data *dto.MemberManageRequest) error {
+	s.log.WithFields(logrus.Fields{
+		"function": "ManageMember",
+		"memberID": id,
+		"data":     *data,
+	}).Info("Attempting to manage member")
+
+	if _, ok := enum.ParseRole(data.Role); data.Role != "" && !ok {
+		s.log.WithFields(logrus.Fields{
+			"function": "ManageMember",
+			"memberID": id,
+			"role":     data.Role,
+		}).Warn("Unknown role")
+		return myerror.NewBadRequestError("unknown role")
+	}
+
+	// members:manage covers account status and cards; only admins may
+	// hand out roles, so staff cannot promote themselves or others
+	if data.Role != "" && helper.RoleFromContext(ctx) != enum.RoleAdmin.String() {
+		s.log.WithFields(logrus.Fields{
+			"function": "ManageMember",
+			"memberID": id,
+			"role":     data.Role,
+		}).Warn("Role change by non-admin")
+		return myerror.NewForbiddenError("only admins can change roles")
+	}
+
+	if _, ok := enum.ParseAccountStatus(data.AccountStatus); data.AccountStatus != "" && !ok {
+		s.log.WithFields(logrus.Fields{
+			"function":      "ManageMember",
+			"memberID":      id,
+			"accountStatus": data.AccountStatus,
+		}).Warn("Unknown account status")
+		return myerror.NewBadRequestError("unknown account status")
+	}
+
+	if data.Barcode != "" && !helper.ValidBarcode(helper.MemberBarcodePrefix(), data.Barcode) {
+		s.log.WithFields(logrus.Fields{
+			"function": "ManageMember",
+			"memberID": id,
+			"barcode":  data.Barcode,
+		}).Warn("Invalid member barcode")
+		return myerror.NewBadRequestError("invalid member barcode")
+	}
+
+	updates := dto.StructToMap(*data)
+	if len(updates) == 0 {
+		return myerror.NewBadRequestError("nothing to update")
+	}
+
+	err := s.repo.Update(ctx, id, &updates)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "ManageMember",
+			"memberID": id,
+		}).WithError(err).Error("Failed to manage member in repository")
+
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			return myerror.NewDuplicateError("member barcode")
+		}
+
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return myerror.NewNotFoundError("member")
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"function": "ManageMember",
+		"memberID": id,
+	}).Info("Successfully managed member")
+	return nil
+}
+
+// SetPIN replaces the member's kiosk PIN and clears any failed attempts,
+// which is also how staff unlock a locked PIN.
+func (s MemberServiceImpl) SetPIN(ctx context.Context, id uuid.UUID, pin string) error {
+	s.log.WithFields(logrus.Fields{
+		"function": "SetPIN",
+		"memberID": id,
+	}).Info("Attempting to set member PIN")
+
+	hashedPIN, err := helper.HashPassword(pin)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "SetPIN",
+			"memberID": id,
+		}).WithError(err).Error("Failed to hash PIN")
+		return myerror.InternalServerErr
+	}
+
+	updates := map[string]interface{}{
+		"pin":          hashedPIN,
+		"pin_failures": 0,
+	}
+
+	err = s.repo.Update(ctx, id, &updates)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"function": "SetPIN",
+			"memberID": id,
+		}).WithError(err).Error("Failed to set PIN in repository")
+
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return myerror.NewNotFoundError("member")
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"function": "SetPIN",
+		"memberID": id,
+	}).Info("Successfully set member PIN")
+	return nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+)
+
+type NotificationService interface {
+	Notify(ctx context.Context, memberID uuid.UUID, kind enum.NotificationKind, message string) error
+	GetByMember(ctx context.Context, memberID uuid.UUID) ([]dto.NotificationResponse, error)
+	MarkRead(ctx context.Context, id uuid.UUID, memberID uuid.UUID) error
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type NotificationServiceImpl struct {
+	log  *log.Logger
+	repo repository.NotificationRepository
+}
+
+func NewNotificationService(log *log.Logger, repo repository.NotificationRepository) NotificationService {
+	return &NotificationServiceImpl{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *NotificationServiceImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+// Notify leaves a message in the member's inbox. It joins the caller's
+// transaction when there is one, so the notice only exists if the change
+// it describes is committed.
+func (s *NotificationServiceImpl) Notify(ctx context.Context, memberID uuid.UUID, kind enum.NotificationKind, message string) error {
+	logger := s.logWithCtx(ctx, "NotificationService.Notify").
+		WithFields(log.Fields{
+			"memberID": memberID,
+			"kind":     kind.String(),
+		})
+
+	notification := model.Notification{
+		MemberID: memberID,
+		Kind:     kind.String(),
+		Message:  message,
+	}
+
+	if err := s.repo.Create(ctx, &notification); err != nil {
+		logger.WithError(err).Error("failed to create notification")
+		return myerror.InternalServerErr
+	}
+
+	logger.WithField("notificationID", notification.ID).Info("member notified")
+	return nil
+}
+
+func (s *NotificationServiceImpl) GetByMember(ctx context.Context, memberID uuid.UUID) ([]dto.NotificationResponse, error) {
+	logger := s.logWithCtx(ctx, "NotificationService.GetByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("received get member notifications request")
+
+	notifications, err := s.repo.GetByMember(ctx, memberID)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch member notifications")
+		return nil, myerror.InternalServerErr
+	}
+
+	response := []dto.NotificationResponse{}
+	for _, v := range notifications {
+		response = append(response, dto.ToNotificationResponse(v))
+	}
+
+	logger.WithField("count", len(response)).Info("member notifications fetched successfully")
+	return response, nil
+}
+
+func (s *NotificationServiceImpl) MarkRead(ctx context.Context, id uuid.UUID, memberID uuid.UUID) error {
+	logger := s.logWithCtx(ctx, "NotificationService.MarkRead").
+		WithFields(log.Fields{
+			"notificationID": id,
+			"memberID":       memberID,
+		})
+
+	logger.Info("received mark notification read request")
+
+	if err := s.repo.MarkRead(ctx, id, memberID, time.Now()); err != nil {
+		logger.WithError(err).Error("failed to mark notification read")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return myerror.NewNotFoundError("notification")
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	logger.Info("notification marked read")
+	return nil
+}
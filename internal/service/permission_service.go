@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+type PermissionService interface {
+	logWithCtx(ctx context.Context, function string) *log.Entry
+	GetByRole(ctx context.Context, role string) ([]string, error)
+	GetAll(ctx context.Context) ([]dto.RolePermissionResponse, error)
+	UpdateRole(ctx context.Context, role string, data *dto.RolePermissionRequest) error
+	SeedDefaults(ctx context.Context) error
+}
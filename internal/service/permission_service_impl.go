@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+	"github.com/nanoLeinz/librarium/internal/myerror"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// admin always holds every permission and is not stored, so it can never be
+// locked out of the permission endpoints.
+var defaultRolePermissions = map[enum.Role][]enum.Permission{
+	enum.RoleLibrarian: {
+		enum.PermCirculationCheckout,
+		enum.PermFinesWaive,
+		enum.PermFinesCollect,
+		enum.PermCirculationOverride,
+	},
+	enum.RoleMember: {},
+}
+
+type PermissionServiceImpl struct {
+	log  *log.Logger
+	repo repository.RolePermissionRepository
+}
+
+func NewPermissionService(log *log.Logger, repo repository.RolePermissionRepository) PermissionService {
+	return &PermissionServiceImpl{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *PermissionServiceImpl) logWithCtx(ctx context.Context, function string) *log.Entry {
+
+	traceID := ctx.Value(helper.KeyCon("traceID"))
+	traceID = traceID.(string)
+
+	logger := s.log.WithFields(log.Fields{
+		"traceID":  traceID,
+		"function": function,
+	})
+
+	return logger
+}
+
+func (s *PermissionServiceImpl) GetByRole(ctx context.Context, role string) ([]string, error) {
+	logger := s.logWithCtx(ctx, "PermissionService.GetByRole").
+		WithField("role", role)
+
+	logger.Info("received get permissions by role request")
+
+	if role == enum.RoleAdmin.String() {
+		perms := []string{}
+		for _, v := range enum.AllPermissions() {
+			perms = append(perms, v.String())
+		}
+		return perms, nil
+	}
+
+	result, err := s.repo.GetByRole(ctx, role)
+	if err != nil {
+		logger.WithError(err).Error("failed to get permissions from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	perms := []string{}
+	for _, v := range result {
+		perms = append(perms, v.Permission)
+	}
+
+	logger.WithField("count", len(perms)).Info("permissions fetched successfully")
+	return perms, nil
+}
+
+func (s *PermissionServiceImpl) GetAll(ctx context.Context) ([]dto.RolePermissionResponse, error) {
+	logger := s.logWithCtx(ctx, "PermissionService.GetAll")
+	logger.Info("received get all role permissions request")
+
+	result, err := s.repo.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get permissions from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	grouped := map[string][]string{}
+	for _, v := range result {
+		grouped[v.Role] = append(grouped[v.Role], v.Permission)
+	}
+
+	response := []dto.RolePermissionResponse{}
+	for _, role := range []enum.Role{enum.RoleAdmin, enum.RoleLibrarian, enum.RoleMember} {
+		perms := grouped[role.String()]
+		if role == enum.RoleAdmin {
+			perms, _ = s.GetByRole(ctx, role.String())
+		}
+		if perms == nil {
+			perms = []string{}
+		}
+
+		response = append(response, dto.RolePermissionResponse{
+			Role:        role.String(),
+			Permissions: perms,
+		})
+	}
+
+	logger.WithField("count", len(response)).Info("role permissions fetched successfully")
+	return response, nil
+}
+
+func (s *PermissionServiceImpl) UpdateRole(ctx context.Context, role string, data *dto.RolePermissionRequest) error {
+	logger := s.logWithCtx(ctx, "PermissionService.UpdateRole").
+		WithFields(log.Fields{
+			"role":        role,
+			"permissions": data.Permissions,
+		})
+
+	logger.Info("received update role permissions request")
+
+	parsed, ok := enum.ParseRole(role)
+	if !ok {
+		logger.Warn("unknown role")
+		return myerror.NewNotFoundError("role")
+	}
+
+	if parsed == enum.RoleAdmin {
+		logger.Warn("admin permissions are not editable")
+		return myerror.NewBadRequestError("admin permissions cannot be changed")
+	}
+
+	seen := map[string]bool{}
+	perms := []string{}
+	for _, v := range data.Permissions {
+		if _, ok := enum.ParsePermission(v); !ok {
+			logger.WithField("permission", v).Warn("unknown permission")
+			return myerror.NewBadRequestError("unknown permission " + v)
+		}
+		if !seen[v] {
+			seen[v] = true
+			perms = append(perms, v)
+		}
+	}
+
+	if err := s.repo.ReplaceRole(ctx, role, perms); err != nil {
+		logger.WithError(err).Error("failed to replace role permissions in repository")
+		return myerror.InternalServerErr
+	}
+
+	logger.Info("role permissions updated successfully")
+	return nil
+}
+
+func (s *PermissionServiceImpl) SeedDefaults(ctx context.Context) error {
+	logger := s.logWithCtx(ctx, "PermissionService.SeedDefaults")
+	logger.Info("seeding default role permissions")
+
+	for role, defaults := range defaultRolePermissions {
+		if len(defaults) == 0 {
+			continue
+		}
+
+		perms := []string{}
+		for _, v := range defaults {
+			perms = append(perms, v.String())
+		}
+
+		// each default is granted once, so permissions added in later
+		// releases still reach the role but ones an admin removed stay removed
+		granted, err := s.repo.SeedRole(ctx, role.String(), perms)
+		if err != nil {
+			logger.WithError(err).Error("failed to seed role permissions")
+			return err
+		}
+
+		logger.WithFields(log.Fields{
+			"role":    role.String(),
+			"granted": granted,
+		}).Info("default permissions seeded")
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/model"
 	"github.com/nanoLeinz/librarium/internal/model/dto"
 	log "github.com/sirupsen/logrus"
 )
@@ -15,4 +16,11 @@ type ReservationService interface {
 	DeleteById(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*dto.ReservationResponse, error)
 	GetAll(ctx context.Context) ([]dto.ReservationResponse, error)
+	GetQueue(ctx context.Context, bookID uuid.UUID) ([]dto.ReservationResponse, error)
+	GetByMember(ctx context.Context, memberID uuid.UUID) ([]dto.ReservationResponse, error)
+	Cancel(ctx context.Context, id uuid.UUID, memberID uuid.UUID) error
+	HoldCopy(ctx context.Context, copy *model.BookCopy) (*dto.ReservationResponse, error)
+	CollectHold(ctx context.Context, copyID uint, memberID uuid.UUID) error
+	ExpireHolds(ctx context.Context) (int, error)
+	ExpireStale(ctx context.Context) (int, error)
 }
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,17 +18,47 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	errHoldNotFound     = myerror.NewBadRequestError("chosen copy unavailable")
+	errHoldOtherMember  = myerror.NewBadRequestError("copy is on hold for another member")
+	errReservationEnded = myerror.NewBadRequestError("reservation is no longer active")
+	errReservStatus     = myerror.NewBadRequestError("status invalid")
+	errReservStatusHold = myerror.NewBadRequestError("reservations become ready or fulfilled through the hold shelf")
+
+	errReservationDuplicate = myerror.NewDuplicateError("active reservation for this book")
+	errReservationLimit     = myerror.NewBadRequestError("reservation limit reached")
+	errReservationAvailable = myerror.NewBadRequestError("book has available copies, borrow one instead")
+	errNotNeededAfterPast   = myerror.NewBadRequestError("not needed after date must be in the future")
+
+	errHoldTypeUnknown    = myerror.NewBadRequestError("unknown hold type")
+	errHoldCopyMissing    = myerror.NewBadRequestError("book_copy_id is required for copy holds")
+	errHoldCopyOutOfStock = myerror.NewBadRequestError("copy cannot be reserved")
+	errHoldNoWork         = myerror.NewBadRequestError("book is not grouped with other editions")
+)
+
 type ReservationServiceImpl struct {
 	repo       repository.ReservationRepository
 	memberRepo repository.MemberRepository
+	copyRepo   repository.BookCopyRepository
+	bookRepo   repository.BookRepository
+	loanRepo   repository.LoanRepository
+	calServ    CalendarService
+	copyServ   BookCopyService
+	tx         repository.Transactor
 	log        *log.Logger
 }
 
-func NewReservationService(log *log.Logger, repo repository.ReservationRepository, memberRepo repository.MemberRepository) ReservationService {
+func NewReservationService(log *log.Logger, tx repository.Transactor, repo repository.ReservationRepository, memberRepo repository.MemberRepository, copyRepo repository.BookCopyRepository, bookRepo repository.BookRepository, loanRepo repository.LoanRepository, calServ CalendarService, copyServ BookCopyService) ReservationService {
 	return &ReservationServiceImpl{
 		repo:       repo,
 		log:        log,
+		tx:         tx,
 		memberRepo: memberRepo,
+		copyRepo:   copyRepo,
+		bookRepo:   bookRepo,
+		loanRepo:   loanRepo,
+		calServ:    calServ,
+		copyServ:   copyServ,
 	}
 }
 
@@ -45,42 +78,97 @@ func (s *ReservationServiceImpl) logWithCtx(ctx context.Context, function string
 func (s *ReservationServiceImpl) Create(ctx context.Context, data *dto.ReservationRequest) (*dto.ReservationResponse, error) {
 	logger := s.logWithCtx(ctx, "ReservationService.Create").
 		WithFields(log.Fields{
-			"bookID":   data.BookID,
-			"memberID": data.MemberID,
+			"bookID":     data.BookID,
+			"bookCopyID": data.BookCopyID,
+			"holdType":   data.HoldType,
+			"memberID":   data.MemberID,
 		})
 
 	logger.Info("received create reservation request")
 
-	member, err := s.memberRepo.GetByID(ctx, data.MemberID)
-	if err != nil {
-		logger.WithError(err).Error("failed to fetch member")
-		switch err {
-		case gorm.ErrRecordNotFound:
-			return nil, myerror.NewNotFoundError("member")
-		default:
-			return nil, myerror.InternalServerErr
+	holdType := enum.TitleHold
+	if data.HoldType != "" {
+		var ok bool
+		if holdType, ok = enum.ParseHoldType(data.HoldType); !ok {
+			logger.WithField("holdType", data.HoldType).Warn("unknown hold type")
+			return nil, errHoldTypeUnknown
 		}
 	}
 
-	if member.AccountStatus == enum.SuspendedAccount.String() || member.AccountStatus != enum.ActiveAccount.String() {
-		logger.WithField("accountStatus", member.AccountStatus).Warn("member account is not active or is suspended")
-		return nil, myerror.NewBadRequestError("account suspended")
+	if holdType == enum.CopyHold && data.BookCopyID == nil {
+		logger.Warn("copy hold without a copy")
+		return nil, errHoldCopyMissing
 	}
 
-	queue := s.repo.GetLastQueue(ctx, data.BookID)
+	if data.NotNeededAfter != nil && !data.NotNeededAfter.After(time.Now()) {
+		logger.WithField("notNeededAfter", data.NotNeededAfter).Warn("not needed after date is in the past")
+		return nil, errNotNeededAfterPast
+	}
 
 	reservation := &model.Reservation{
 		BookID:          data.BookID,
 		MemberID:        data.MemberID,
+		BookCopyID:      data.BookCopyID,
+		HoldType:        holdType.String(),
 		Status:          enum.PendingReserv.String(),
 		ReservationDate: time.Now().Local(),
-		QueuePosition:   queue + 1,
+		NotNeededAfter:  data.NotNeededAfter,
 	}
 
-	result, err := s.repo.Create(ctx, reservation)
+	var result *model.Reservation
+
+	// the queue lock makes reading the tail position and inserting behind
+	// it atomic for concurrent reservations of the same title, the member
+	// lock does the same for the member's reservation count
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		member, err := s.memberRepo.GetByIDForUpdate(ctx, data.MemberID)
+		if err != nil {
+			logger.WithError(err).Error("failed to fetch member")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return myerror.NewNotFoundError("member")
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if member.AccountStatus == enum.SuspendedAccount.String() || member.AccountStatus != enum.ActiveAccount.String() {
+			logger.WithField("accountStatus", member.AccountStatus).Warn("member account is not active or is suspended")
+			return myerror.NewBadRequestError("account suspended")
+		}
+
+		if err := s.resolveTarget(ctx, holdType, reservation); err != nil {
+			return err
+		}
+
+		if err := s.repo.LockQueue(ctx, reservation.QueueKey); err != nil {
+			logger.WithError(err).Error("failed to lock reservation queue")
+			return myerror.InternalServerErr
+		}
+
+		if err := s.checkRules(ctx, holdType, reservation); err != nil {
+			return err
+		}
+
+		last, err := s.repo.GetLastQueue(ctx, reservation.QueueKey)
+		if err != nil {
+			logger.WithError(err).Error("failed to get last queue position")
+			return myerror.InternalServerErr
+		}
+
+		reservation.QueuePosition = last + 1
+
+		result, err = s.repo.Create(ctx, reservation)
+		if err != nil {
+			logger.WithError(err).Error("failed to create reservation in repository")
+			return myerror.InternalServerErr
+		}
+
+		return nil
+	})
+
 	if err != nil {
-		logger.WithError(err).Error("failed to create reservation in repository")
-		return nil, myerror.InternalServerErr
+		return nil, myerror.FromError(err)
 	}
 
 	logger.WithFields(log.Fields{
@@ -88,10 +176,131 @@ func (s *ReservationServiceImpl) Create(ctx context.Context, data *dto.Reservati
 		"queuePosition": result.QueuePosition,
 	}).Info("reservation created successfully")
 
-	res := dto.ToReservationResponse(*result)
+	res := s.toResponses(ctx, []model.Reservation{*result})[0]
 	return &res, nil
 }
 
+// resolveTarget fills in the book, copy and queue a new reservation waits
+// on. Copy holds take their book from the copy; work holds need the book
+// to be grouped into a work.
+func (s *ReservationServiceImpl) resolveTarget(ctx context.Context, holdType enum.HoldType, reservation *model.Reservation) error {
+	logger := s.logWithCtx(ctx, "ReservationService.resolveTarget").
+		WithField("holdType", holdType.String())
+
+	if holdType == enum.CopyHold {
+		copy, err := s.copyRepo.GetByID(ctx, *reservation.BookCopyID)
+		if err != nil {
+			logger.WithError(err).Error("failed to fetch book copy")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return myerror.NewNotFoundError("book copy")
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if copy.Status == enum.LostCopy.String() || copy.Status == enum.DamagedCopy.String() {
+			logger.WithField("copyStatus", copy.Status).Warn("copy is out of circulation")
+			return errHoldCopyOutOfStock
+		}
+
+		reservation.BookID = copy.BookID
+		reservation.QueueKey = helper.CopyQueueKey(copy.ID)
+	}
+
+	book, err := s.bookRepo.GetByID(ctx, reservation.BookID)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch book")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return myerror.NewNotFoundError("book")
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	switch holdType {
+	case enum.TitleHold:
+		reservation.BookCopyID = nil
+		reservation.QueueKey = helper.TitleQueueKey(book.ID)
+	case enum.WorkHold:
+		if book.WorkKey == "" {
+			logger.WithField("bookID", book.ID).Warn("book has no work key")
+			return errHoldNoWork
+		}
+		reservation.BookCopyID = nil
+		reservation.QueueKey = helper.WorkQueueKey(book.WorkKey)
+	}
+
+	return nil
+}
+
+// checkRules enforces the reservation rules for a member and queue. It
+// runs under the member and queue locks taken by Create.
+func (s *ReservationServiceImpl) checkRules(ctx context.Context, holdType enum.HoldType, reservation *model.Reservation) error {
+	logger := s.logWithCtx(ctx, "ReservationService.checkRules").
+		WithFields(log.Fields{
+			"memberID": reservation.MemberID,
+			"queueKey": reservation.QueueKey,
+		})
+
+	same, err := s.repo.CountActive(ctx, reservation.MemberID, &reservation.QueueKey)
+	if err != nil {
+		logger.WithError(err).Error("failed to count active reservations for queue")
+		return myerror.InternalServerErr
+	}
+
+	if same > 0 {
+		logger.Warn("member already holds an active reservation in queue")
+		return errReservationDuplicate
+	}
+
+	active, err := s.repo.CountActive(ctx, reservation.MemberID, nil)
+	if err != nil {
+		logger.WithError(err).Error("failed to count active reservations")
+		return myerror.InternalServerErr
+	}
+
+	if active >= int64(helper.GetEnvInt("RESERVATION_MAX_ACTIVE", 5)) {
+		logger.WithField("active", active).Warn("member reached max active reservations")
+		return errReservationLimit
+	}
+
+	if helper.GetEnvBool("RESERVATION_ONLY_WHEN_UNAVAILABLE", true) {
+		available, err := s.countCopies(ctx, holdType, reservation, enum.AvailableCopy.String())
+		if err != nil {
+			logger.WithError(err).Error("failed to count available copies")
+			return myerror.InternalServerErr
+		}
+
+		if available > 0 {
+			logger.WithField("available", available).Warn("copies that fill the hold are on the shelf")
+			return errReservationAvailable
+		}
+	}
+
+	return nil
+}
+
+// countCopies counts the copies in statuses that could fill reservation.
+func (s *ReservationServiceImpl) countCopies(ctx context.Context, holdType enum.HoldType, reservation *model.Reservation, statuses ...string) (int64, error) {
+	switch holdType {
+	case enum.CopyHold:
+		copy, err := s.copyRepo.GetByID(ctx, *reservation.BookCopyID)
+		if err != nil {
+			return 0, err
+		}
+		if slices.Contains(statuses, copy.Status) {
+			return 1, nil
+		}
+		return 0, nil
+	case enum.WorkHold:
+		return s.copyRepo.CountByWork(ctx, strings.TrimPrefix(reservation.QueueKey, helper.WorkQueueKey("")), statuses...)
+	default:
+		return s.copyRepo.CountByStatus(ctx, reservation.BookID, statuses...)
+	}
+}
+
 func (s *ReservationServiceImpl) Update(ctx context.Context, id uuid.UUID, data *dto.ReservationRequest) error {
 	logger := s.logWithCtx(ctx, "ReservationService.Update").
 		WithFields(log.Fields{
@@ -103,15 +312,21 @@ func (s *ReservationServiceImpl) Update(ctx context.Context, id uuid.UUID, data
 
 	logger.Info("received update reservation request")
 
-	req := model.Reservation{
-		ID:        id,
-		Status:    data.Status,
-		UpdatedAt: time.Now().Local(),
+	status, ok := enum.ParseReservStatus(strings.ToLower(data.Status))
+	if !ok {
+		return errReservStatus
+	}
+
+	// ready and fulfilled need a copy on the hold shelf, which only
+	// HoldCopy and CollectHold manage
+	if status == enum.ReadyReserv || status == enum.FulfilledReserv {
+		logger.Warn("status can only be set through the hold shelf")
+		return errReservStatusHold
 	}
 
-	err := s.repo.Update(ctx, req)
+	current, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		logger.WithError(err).Error("failed to update reservation in repository")
+		logger.WithError(err).Error("failed to get reservation by ID")
 		switch err {
 		case gorm.ErrRecordNotFound:
 			return myerror.NewNotFoundError("reservation")
@@ -120,16 +335,29 @@ func (s *ReservationServiceImpl) Update(ctx context.Context, id uuid.UUID, data
 		}
 	}
 
-	logger.Info("reservation updated successfully")
+	switch {
+	case current.Status == status.String():
+		return nil
+	case status == enum.PendingReserv:
+		err = errReservationEnded
+	case current.Status == enum.PendingReserv.String():
+		err = s.changeQueued(ctx, id, func(ctx context.Context, current *model.Reservation) error {
+			return s.closePending(ctx, current, status)
+		})
+	case current.Status == enum.ReadyReserv.String():
+		err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.releaseHold(ctx, *current, status)
+		})
+	default:
+		err = errReservationEnded
+	}
 
-	if req.Status != "" {
-		logger.WithFields(log.Fields{
-			"reservationID": req.ID,
-			"bookID":        req.BookID,
-		}).Info("updating related queue due to status change")
-		_ = s.repo.UpdateRelatedQueue(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to update reservation")
+		return myerror.FromError(err)
 	}
 
+	logger.Info("reservation updated successfully")
 	return nil
 }
 
@@ -139,9 +367,9 @@ func (s *ReservationServiceImpl) DeleteById(ctx context.Context, id uuid.UUID) e
 
 	logger.Info("received delete reservation request")
 
-	err := s.repo.DeleteById(ctx, id)
+	current, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		logger.WithError(err).Error("failed to delete reservation in repository")
+		logger.WithError(err).Error("failed to get reservation by ID")
 		switch err {
 		case gorm.ErrRecordNotFound:
 			return myerror.NewNotFoundError("reservation")
@@ -150,10 +378,83 @@ func (s *ReservationServiceImpl) DeleteById(ctx context.Context, id uuid.UUID) e
 		}
 	}
 
+	// a ready hold gives its copy to the next member, or back to the
+	// shelf, before it goes
+	if current.Status == enum.ReadyReserv.String() {
+		err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.releaseHold(ctx, *current, enum.CancelledReserv); err != nil && err != errReservationEnded {
+				return err
+			}
+
+			if err := s.repo.DeleteById(ctx, id); err != nil {
+				logger.WithError(err).Error("failed to delete reservation in repository")
+				return myerror.InternalServerErr
+			}
+			return nil
+		})
+	} else {
+		err = s.changeQueued(ctx, id, func(ctx context.Context, current *model.Reservation) error {
+			return s.repo.DeleteById(ctx, id)
+		})
+	}
+
+	if err != nil {
+		logger.WithError(err).Error("failed to delete reservation")
+		return myerror.FromError(err)
+	}
+
 	logger.Info("reservation deleted successfully")
 	return nil
 }
 
+// changeQueued applies change to a reservation under its title's queue
+// lock and recompacts the queue afterwards.
+func (s *ReservationServiceImpl) changeQueued(ctx context.Context, id uuid.UUID, change func(ctx context.Context, current *model.Reservation) error) error {
+	logger := s.logWithCtx(ctx, "ReservationService.changeQueued").
+		WithField("reservationID", id)
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			logger.WithError(err).Error("failed to get reservation by ID")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return myerror.NewNotFoundError("reservation")
+			default:
+				return myerror.InternalServerErr
+			}
+		}
+
+		if err := s.repo.LockQueue(ctx, current.QueueKey); err != nil {
+			logger.WithError(err).Error("failed to lock reservation queue")
+			return myerror.InternalServerErr
+		}
+
+		if err := change(ctx, current); err != nil {
+			logger.WithError(err).Error("failed to change reservation")
+			switch err {
+			case gorm.ErrRecordNotFound:
+				return myerror.NewNotFoundError("reservation")
+			default:
+				return myerror.FromError(err)
+			}
+		}
+
+		if err := s.repo.RecompactQueue(ctx, current.QueueKey); err != nil {
+			logger.WithError(err).Error("failed to recompact reservation queue")
+			return myerror.InternalServerErr
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return myerror.FromError(err)
+	}
+
+	return nil
+}
+
 func (s *ReservationServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*dto.ReservationResponse, error) {
 	logger := s.logWithCtx(ctx, "ReservationService.GetByID").
 		WithField("reservationID", id)
@@ -171,7 +472,7 @@ func (s *ReservationServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*dt
 		}
 	}
 
-	response := dto.ToReservationResponse(*res)
+	response := s.toResponses(ctx, []model.Reservation{*res})[0]
 	logger.WithField("reservationID", response.ID).Info("reservation fetched successfully")
 	return &response, nil
 }
@@ -199,3 +500,428 @@ func (s *ReservationServiceImpl) GetAll(ctx context.Context) ([]dto.ReservationR
 	logger.WithField("count", len(response)).Info("all reservations fetched successfully")
 	return response, nil
 }
+
+// HoldCopy puts copy on the hold shelf for the oldest pending reservation
+// it can fill, whether held on the copy, its title or its work, and moves
+// it to reserved. It returns nil, leaving the copy untouched, when nobody
+// is waiting.
+func (s *ReservationServiceImpl) HoldCopy(ctx context.Context, copy *model.BookCopy) (*dto.ReservationResponse, error) {
+	logger := s.logWithCtx(ctx, "ReservationService.HoldCopy").
+		WithFields(log.Fields{
+			"bookID":     copy.BookID,
+			"bookCopyID": copy.ID,
+		})
+
+	book, err := s.bookRepo.GetByID(ctx, copy.BookID)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch book")
+		return nil, myerror.InternalServerErr
+	}
+
+	keys := helper.CopyQueueKeys(copy.ID, book.ID, book.WorkKey)
+	for _, key := range keys {
+		if err := s.repo.LockQueue(ctx, key); err != nil {
+			logger.WithError(err).Error("failed to lock reservation queue")
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	next, err := s.repo.GetNextInQueue(ctx, keys)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Info("no pending reservation for copy")
+			return nil, nil
+		}
+		logger.WithError(err).Error("failed to get next reservation in queue")
+		return nil, myerror.InternalServerErr
+	}
+
+	calendar, err := s.calServ.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deadline := calendar.NextOpenDay(now.AddDate(0, 0, helper.GetEnvInt("RESERVATION_PICKUP_DAYS", 3)))
+
+	if err := s.repo.MarkReady(ctx, next.ID, copy.ID, now, deadline); err != nil {
+		logger.WithError(err).Error("failed to mark reservation ready")
+		return nil, myerror.InternalServerErr
+	}
+
+	if err := s.repo.RecompactQueue(ctx, next.QueueKey); err != nil {
+		logger.WithError(err).Error("failed to recompact reservation queue")
+		return nil, myerror.InternalServerErr
+	}
+
+	if err := s.copyServ.Transition(ctx, copy, enum.ReservedCopy, "held for reservation"); err != nil {
+		return nil, err
+	}
+
+	next.Status = enum.ReadyReserv.String()
+	next.QueuePosition = 0
+	next.BookCopyID = &copy.ID
+	next.ReadyAt = &now
+	next.PickupDeadline = &deadline
+
+	logger.WithFields(log.Fields{
+		"reservationID":  next.ID,
+		"pickupDeadline": deadline,
+	}).Info("copy placed on hold shelf")
+
+	response := dto.ToReservationResponse(*next)
+	return &response, nil
+}
+
+// CollectHold fulfils the ready reservation holding copyID when memberID
+// is the one it is held for.
+func (s *ReservationServiceImpl) CollectHold(ctx context.Context, copyID uint, memberID uuid.UUID) error {
+	logger := s.logWithCtx(ctx, "ReservationService.CollectHold").
+		WithFields(log.Fields{
+			"bookCopyID": copyID,
+			"memberID":   memberID,
+		})
+
+	hold, err := s.repo.GetReadyByCopy(ctx, copyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("reserved copy has no ready reservation")
+			return errHoldNotFound
+		}
+		logger.WithError(err).Error("failed to get ready reservation by copy")
+		return myerror.InternalServerErr
+	}
+
+	if hold.MemberID != memberID {
+		logger.WithField("reservationID", hold.ID).Warn("copy is held for another member")
+		return errHoldOtherMember
+	}
+
+	err = s.repo.Update(ctx, model.Reservation{
+		ID:        hold.ID,
+		Status:    enum.FulfilledReserv.String(),
+		UpdatedAt: time.Now().Local(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to fulfil reservation")
+		return myerror.InternalServerErr
+	}
+
+	logger.WithField("reservationID", hold.ID).Info("reservation fulfilled")
+	return nil
+}
+
+// ExpireHolds expires ready reservations past their pickup deadline and
+// rolls each copy to the next member in the queue, or back to the shelf.
+func (s *ReservationServiceImpl) ExpireHolds(ctx context.Context) (int, error) {
+	logger := s.logWithCtx(ctx, "ReservationService.ExpireHolds")
+	logger.Info("expiring uncollected holds")
+
+	holds, err := s.repo.GetExpiredHolds(ctx, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("failed to get expired holds")
+		return 0, myerror.InternalServerErr
+	}
+
+	expired := 0
+	for _, v := range holds {
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.releaseHold(ctx, v, enum.ExpiredReserv)
+		})
+
+		if err == errReservationEnded {
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).WithField("reservationID", v.ID).Error("failed to expire hold")
+			continue
+		}
+		expired++
+	}
+
+	logger.WithFields(log.Fields{
+		"holds":   len(holds),
+		"expired": expired,
+	}).Info("uncollected holds expired")
+	return expired, nil
+}
+
+// releaseHold closes a ready reservation with status and passes its copy
+// to the next member in the queue, or back to the shelf.
+func (s *ReservationServiceImpl) releaseHold(ctx context.Context, hold model.Reservation, status enum.ReservStatus) error {
+	logger := s.logWithCtx(ctx, "ReservationService.releaseHold").
+		WithFields(log.Fields{
+			"reservationID": hold.ID,
+			"status":        status.String(),
+		})
+
+	if hold.BookCopyID == nil {
+		logger.Warn("ready reservation holds no copy, closing it")
+		return s.closeHold(ctx, hold.ID, status)
+	}
+
+	copy, err := s.copyRepo.GetByIDForUpdate(ctx, *hold.BookCopyID)
+	if err != nil {
+		logger.WithError(err).Error("failed to lock book copy by ID")
+		return myerror.InternalServerErr
+	}
+
+	// the hold may have been collected while we waited on the lock
+	current, err := s.repo.GetByID(ctx, hold.ID)
+	if err != nil || current.Status != enum.ReadyReserv.String() {
+		logger.Info("hold no longer ready, skipping")
+		return errReservationEnded
+	}
+
+	if err := s.closeHold(ctx, hold.ID, status); err != nil {
+		return err
+	}
+
+	next, err := s.HoldCopy(ctx, copy)
+	if err != nil {
+		return err
+	}
+
+	if next == nil {
+		return s.copyServ.Transition(ctx, copy, enum.AvailableCopy, "hold released")
+	}
+
+	return nil
+}
+
+func (s *ReservationServiceImpl) closeHold(ctx context.Context, id uuid.UUID, status enum.ReservStatus) error {
+	err := s.repo.Update(ctx, model.Reservation{
+		ID:        id,
+		Status:    status.String(),
+		UpdatedAt: time.Now().Local(),
+	})
+	if err != nil {
+		s.logWithCtx(ctx, "ReservationService.closeHold").
+			WithField("reservationID", id).
+			WithError(err).Error("failed to close reservation")
+		return myerror.InternalServerErr
+	}
+
+	return nil
+}
+
+func (s *ReservationServiceImpl) GetQueue(ctx context.Context, bookID uuid.UUID) ([]dto.ReservationResponse, error) {
+	logger := s.logWithCtx(ctx, "ReservationService.GetQueue").
+		WithField("bookID", bookID)
+
+	logger.Info("received get reservation queue request")
+
+	book, err := s.bookRepo.GetByID(ctx, bookID)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch book")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return nil, myerror.NewNotFoundError("book")
+		default:
+			return nil, myerror.InternalServerErr
+		}
+	}
+
+	copies, err := s.copyRepo.GetByBook(ctx, bookID)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch book copies")
+		return nil, myerror.InternalServerErr
+	}
+
+	// everyone waiting on a copy of this book: title holds, holds on
+	// one of its copies and holds on its work
+	keys := []string{helper.TitleQueueKey(book.ID)}
+	for _, v := range copies {
+		keys = append(keys, helper.CopyQueueKey(v.ID))
+	}
+	if book.WorkKey != "" {
+		keys = append(keys, helper.WorkQueueKey(book.WorkKey))
+	}
+
+	res, err := s.repo.GetQueue(ctx, keys)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch queue from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	response := s.toResponses(ctx, res)
+
+	logger.WithField("count", len(response)).Info("reservation queue fetched successfully")
+	return response, nil
+}
+
+func (s *ReservationServiceImpl) GetByMember(ctx context.Context, memberID uuid.UUID) ([]dto.ReservationResponse, error) {
+	logger := s.logWithCtx(ctx, "ReservationService.GetByMember").
+		WithField("memberID", memberID)
+
+	logger.Info("received get member reservations request")
+
+	res, err := s.repo.GetByMember(ctx, memberID)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch member reservations from repository")
+		return nil, myerror.InternalServerErr
+	}
+
+	response := s.toResponses(ctx, res)
+
+	logger.WithField("count", len(response)).Info("member reservations fetched successfully")
+	return response, nil
+}
+
+// Cancel cancels a member's own pending or ready reservation. Cancelling a
+// ready hold hands the copy on to the next member in the queue.
+func (s *ReservationServiceImpl) Cancel(ctx context.Context, id uuid.UUID, memberID uuid.UUID) error {
+	logger := s.logWithCtx(ctx, "ReservationService.Cancel").
+		WithFields(log.Fields{
+			"reservationID": id,
+			"memberID":      memberID,
+		})
+
+	logger.Info("received cancel reservation request")
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("failed to get reservation by ID")
+		switch err {
+		case gorm.ErrRecordNotFound:
+			return myerror.NewNotFoundError("reservation")
+		default:
+			return myerror.InternalServerErr
+		}
+	}
+
+	if current.MemberID != memberID {
+		logger.Warn("member tried to cancel someone else's reservation")
+		return myerror.NewNotFoundError("reservation")
+	}
+
+	switch current.Status {
+	case enum.PendingReserv.String():
+		err = s.changeQueued(ctx, id, func(ctx context.Context, current *model.Reservation) error {
+			return s.closePending(ctx, current, enum.CancelledReserv)
+		})
+	case enum.ReadyReserv.String():
+		err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.releaseHold(ctx, *current, enum.CancelledReserv)
+		})
+	default:
+		err = errReservationEnded
+	}
+
+	if err != nil {
+		logger.WithError(err).Error("failed to cancel reservation")
+		return myerror.FromError(err)
+	}
+
+	logger.Info("reservation cancelled successfully")
+	return nil
+}
+
+// closePending moves a pending reservation to status. It expects the
+// queue lock to be held and refuses reservations that already left the
+// queue.
+func (s *ReservationServiceImpl) closePending(ctx context.Context, current *model.Reservation, status enum.ReservStatus) error {
+	if current.Status != enum.PendingReserv.String() {
+		return errReservationEnded
+	}
+
+	return s.repo.Update(ctx, model.Reservation{
+		ID:        current.ID,
+		Status:    status.String(),
+		UpdatedAt: time.Now().Local(),
+	})
+}
+
+// ExpireStale expires pending reservations past their not needed after
+// date, and those older than RESERVATION_MAX_PENDING_DAYS when it is set.
+func (s *ReservationServiceImpl) ExpireStale(ctx context.Context) (int, error) {
+	logger := s.logWithCtx(ctx, "ReservationService.ExpireStale")
+	logger.Info("expiring stale reservations")
+
+	now := time.Now()
+
+	var createdBefore *time.Time
+	if days := helper.GetEnvInt("RESERVATION_MAX_PENDING_DAYS", 0); days > 0 {
+		cutoff := now.AddDate(0, 0, -days)
+		createdBefore = &cutoff
+	}
+
+	stale, err := s.repo.GetStale(ctx, now, createdBefore)
+	if err != nil {
+		logger.WithError(err).Error("failed to get stale reservations")
+		return 0, myerror.InternalServerErr
+	}
+
+	expired := 0
+	for _, v := range stale {
+		err := s.changeQueued(ctx, v.ID, func(ctx context.Context, current *model.Reservation) error {
+			return s.closePending(ctx, current, enum.ExpiredReserv)
+		})
+
+		if err == errReservationEnded {
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).WithField("reservationID", v.ID).Error("failed to expire stale reservation")
+			continue
+		}
+		expired++
+	}
+
+	logger.WithFields(log.Fields{
+		"stale":   len(stale),
+		"expired": expired,
+	}).Info("stale reservations expired")
+	return expired, nil
+}
+
+// toResponses converts reservations and adds an estimated wait to the
+// pending ones: the rounds of loans needed to reach their queue position
+// across the circulating copies that can fill the hold, times the title's
+// average loan length.
+func (s *ReservationServiceImpl) toResponses(ctx context.Context, reservations []model.Reservation) []dto.ReservationResponse {
+	logger := s.logWithCtx(ctx, "ReservationService.toResponses")
+
+	type stats struct {
+		copies int64
+		days   float64
+	}
+	cache := map[string]*stats{}
+
+	response := []dto.ReservationResponse{}
+	for _, v := range reservations {
+		res := dto.ToReservationResponse(v)
+
+		if v.Status == enum.PendingReserv.String() && v.QueuePosition > 0 {
+			st, ok := cache[v.QueueKey]
+			if !ok {
+				st = &stats{}
+
+				holdType, _ := enum.ParseHoldType(v.HoldType)
+				copies, err := s.countCopies(ctx, holdType, &v, enum.AvailableCopy.String(), enum.LoanedCopy.String(), enum.ReservedCopy.String())
+				if err != nil {
+					logger.WithError(err).Warn("failed to count circulating copies")
+				}
+				st.copies = copies
+
+				days, err := s.loanRepo.AverageLoanDays(ctx, v.BookID)
+				if err != nil || days <= 0 {
+					days = float64(helper.DefaultCirculationPolicy().LoanDays)
+				}
+				st.days = days
+
+				cache[v.QueueKey] = st
+			}
+
+			if st.copies > 0 {
+				rounds := math.Ceil(float64(v.QueuePosition) / float64(st.copies))
+				wait := int(math.Ceil(rounds * st.days))
+				res.EstimatedWait = &wait
+			}
+		}
+
+		response = append(response, res)
+	}
+
+	return response
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/model/dto"
+)
+
+func TestCreateReservationConcurrentQueuePositions(t *testing.T) {
+	s := newTestServices(t)
+
+	const holds = 10
+
+	members := s.newMembers(t, holds)
+	book, _ := s.newBook(t, 1, enum.LoanedCopy)
+
+	start := make(chan struct{})
+	errs := make([]error, holds)
+
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, errs[i] = s.reserv.Create(testContext(), &dto.ReservationRequest{
+				BookID:   book.ID,
+				MemberID: member.ID,
+			})
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("reservation failed: %v", err)
+		}
+	}
+
+	reservations := []model.Reservation{}
+	s.db.Where("queue_key = ?", helper.TitleQueueKey(book.ID)).Find(&reservations)
+
+	positions := []int{}
+	for _, v := range reservations {
+		positions = append(positions, v.QueuePosition)
+	}
+	slices.Sort(positions)
+
+	want := []int{}
+	for i := 1; i <= holds; i++ {
+		want = append(want, i)
+	}
+
+	if !slices.Equal(positions, want) {
+		t.Errorf("queue positions = %v, want %v", positions, want)
+	}
+}
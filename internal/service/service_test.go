@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nanoLeinz/librarium/internal/enum"
+	"github.com/nanoLeinz/librarium/internal/helper"
+	"github.com/nanoLeinz/librarium/internal/model"
+	"github.com/nanoLeinz/librarium/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// testServices wires the services the way main does, against the database
+// in TEST_DSN. Tests that need it are skipped when TEST_DSN is unset, except
+// in CI where they fail instead.
+type testServices struct {
+	db     *gorm.DB
+	loan   LoanService
+	reserv ReservationService
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DSN")
+	if dsn == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("TEST_DSN must be set in CI")
+		}
+		t.Skip("TEST_DSN not set")
+	}
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+	log.SetOutput(io.Discard)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("connecting to test database: %v", err)
+	}
+	helper.AutoMigrateModels(db)
+
+	tx := repository.NewTransactor(logger, db)
+
+	memberRepo := repository.NewMemberRepository(db, logger)
+	loanRepo := repository.NewLoanRepository(logger, db)
+	eventRepo := repository.NewItemEventRepository(logger, db)
+	copyRepo := repository.NewBookCopyRepositoryImpl(logger, db)
+	bookRepo := repository.NewBookRepositoryImpl(logger, db)
+	reservRepo := repository.NewReservationRepository(logger, db)
+
+	copyServ := NewBookCopyService(logger, tx, copyRepo, loanRepo, reservRepo, eventRepo)
+	calServ := NewCalendarService(logger, tx, repository.NewCalendarRepository(logger, db))
+	policyServ := NewCirculationPolicyService(logger, repository.NewCirculationPolicyRepository(logger, db))
+	fineServ := NewFineService(logger, repository.NewFineRepository(logger, db), memberRepo, loanRepo, copyRepo, bookRepo, policyServ, calServ)
+	notifServ := NewNotificationService(logger, repository.NewNotificationRepository(logger, db))
+	reservServ := NewReservationService(logger, tx, reservRepo, memberRepo, copyRepo, bookRepo, loanRepo, calServ, copyServ)
+	loanServ := NewLoanServiceImpl(logger, tx, loanRepo, memberRepo, copyRepo, bookRepo, reservRepo, reservServ, fineServ, policyServ, calServ, notifServ, copyServ)
+
+	return &testServices{
+		db:     db,
+		loan:   loanServ,
+		reserv: reservServ,
+	}
+}
+
+func testContext() context.Context {
+	return context.WithValue(context.Background(), helper.KeyCon("traceID"), "TEST")
+}
+
+// newMembers creates n active members, removed again when the test ends.
+func (s *testServices) newMembers(t *testing.T, n int) []model.Member {
+	t.Helper()
+
+	members := make([]model.Member, n)
+	for i := range members {
+		members[i] = model.Member{
+			Email:         uuid.NewString() + "@librarium.test",
+			FullName:      "Test Member",
+			Role:          enum.RoleMember.String(),
+			AccountStatus: enum.ActiveAccount.String(),
+		}
+	}
+
+	if err := s.db.Create(&members).Error; err != nil {
+		t.Fatalf("creating members: %v", err)
+	}
+
+	t.Cleanup(func() {
+		for _, v := range members {
+			s.db.Unscoped().Where("member_id = ?", v.ID).Delete(&model.Reservation{})
+			s.db.Unscoped().Where("member_id = ?", v.ID).Delete(&model.Loan{})
+			s.db.Unscoped().Delete(&v)
+		}
+	})
+
+	return members
+}
+
+// newBook creates a book with copies copies in status, removed again when
+// the test ends.
+func (s *testServices) newBook(t *testing.T, copies int, status enum.CopyStatus) (model.Book, []model.BookCopy) {
+	t.Helper()
+
+	book := model.Book{
+		Title: "Concurrency Test",
+		ISBN:  uuid.NewString(),
+	}
+
+	if err := s.db.Create(&book).Error; err != nil {
+		t.Fatalf("creating book: %v", err)
+	}
+
+	items := []model.BookCopy{}
+	for range copies {
+		items = append(items, model.BookCopy{
+			BookID: book.ID,
+			Status: status.String(),
+		})
+	}
+
+	if len(items) > 0 {
+		if err := s.db.Create(&items).Error; err != nil {
+			t.Fatalf("creating copies: %v", err)
+		}
+	}
+
+	t.Cleanup(func() {
+		for _, v := range items {
+			s.db.Unscoped().Where("book_copy_id = ?", v.ID).Delete(&model.ItemEvent{})
+			s.db.Unscoped().Where("book_copy_id = ?", v.ID).Delete(&model.Loan{})
+			s.db.Unscoped().Delete(&v)
+		}
+		s.db.Unscoped().Where("book_id = ?", book.ID).Delete(&model.Reservation{})
+		s.db.Unscoped().Delete(&book)
+	})
+
+	return book, items
+}
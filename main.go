@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -14,6 +18,7 @@ import (
 	"github.com/nanoLeinz/librarium/internal/helper"
 	"github.com/nanoLeinz/librarium/internal/repository"
 	"github.com/nanoLeinz/librarium/internal/router"
+	"github.com/nanoLeinz/librarium/internal/scheduler"
 	"github.com/nanoLeinz/librarium/internal/service"
 )
 
@@ -38,35 +43,112 @@ func main() {
 	db := helper.InitDatabase()
 	helper.AutoMigrateModels(db)
 
-	MemberRepo := repository.NewMemberRepository(db, log.StandardLogger())
-	MemberServ := service.NewMemberServiceImpl(MemberRepo, log.StandardLogger())
+	ctx := context.WithValue(context.Background(), helper.KeyCon("traceID"), "STARTUP")
 
 	validate := validator.New()
 
+	Transactor := repository.NewTransactor(log.StandardLogger(), db)
+
+	PermissionRepo := repository.NewRolePermissionRepository(log.StandardLogger(), db)
+	PermissionServ := service.NewPermissionService(log.StandardLogger(), PermissionRepo)
+	PermissionHandler := controller.NewPermissionController(log.StandardLogger(), PermissionServ, validate)
+
+	if err := PermissionServ.SeedDefaults(ctx); err != nil {
+		log.WithError(err).Error("failed seeding default role permissions")
+	}
+
+	MemberRepo := repository.NewMemberRepository(db, log.StandardLogger())
+	MemberServ := service.NewMemberServiceImpl(MemberRepo, log.StandardLogger())
+
 	MemberHandler := controller.NewMemberController(MemberServ, validate, log.StandardLogger())
-	AuthHandler := controller.NewAuthController(MemberServ, validate, log.StandardLogger())
+	AuthHandler := controller.NewAuthController(MemberServ, PermissionServ, validate, log.StandardLogger())
 
 	AuthorRepo := repository.NewAuthorRepositoryImpl(log.StandardLogger(), db)
 	AuthorServ := service.NewAuthorServiceImpl(log.StandardLogger(), AuthorRepo)
 	AuthorHandler := controller.NewAuthorController(AuthorServ, log.StandardLogger())
 
+	LoanRepo := repository.NewLoanRepository(log.StandardLogger(), db)
+	ItemEventRepo := repository.NewItemEventRepository(log.StandardLogger(), db)
+	ReservRepo := repository.NewReservationRepository(log.StandardLogger(), db)
+
 	BookCopyRepo := repository.NewBookCopyRepositoryImpl(log.StandardLogger(), db)
-	BookCopyServ := service.NewBookCopyService(log.StandardLogger(), BookCopyRepo)
+	BookCopyServ := service.NewBookCopyService(log.StandardLogger(), Transactor, BookCopyRepo, LoanRepo, ReservRepo, ItemEventRepo)
 	BookCopyHandler := controller.NewBookCopyController(log.StandardLogger(), BookCopyServ)
 
 	BookRepo := repository.NewBookRepositoryImpl(log.StandardLogger(), db)
-	BookServ := service.NewBookServiceImpl(log.StandardLogger(), BookRepo, BookCopyRepo)
+	BookServ := service.NewBookServiceImpl(log.StandardLogger(), BookRepo, BookCopyServ)
 	BookHandler := controller.NewBookController(BookServ, log.StandardLogger())
 
-	LoanRepo := repository.NewLoanRepository(log.StandardLogger(), db)
-	LoanServ := service.NewLoanServiceImpl(log.StandardLogger(), LoanRepo, MemberRepo, BookCopyRepo)
-	LoanHandler := controller.NewLoanController(log.StandardLogger(), LoanServ)
+	CalendarRepo := repository.NewCalendarRepository(log.StandardLogger(), db)
+	CalendarServ := service.NewCalendarService(log.StandardLogger(), Transactor, CalendarRepo)
+	CalendarHandler := controller.NewCalendarController(log.StandardLogger(), CalendarServ, validate)
 
-	ReservRepo := repository.NewReservationRepository(log.StandardLogger(), db)
-	ReservServ := service.NewReservationService(log.StandardLogger(), ReservRepo, MemberRepo)
+	PolicyRepo := repository.NewCirculationPolicyRepository(log.StandardLogger(), db)
+	PolicyServ := service.NewCirculationPolicyService(log.StandardLogger(), PolicyRepo)
+	PolicyHandler := controller.NewCirculationPolicyController(log.StandardLogger(), PolicyServ, validate)
+
+	FineRepo := repository.NewFineRepository(log.StandardLogger(), db)
+	FineServ := service.NewFineService(log.StandardLogger(), FineRepo, MemberRepo, LoanRepo, BookCopyRepo, BookRepo, PolicyServ, CalendarServ)
+	FineHandler := controller.NewFineController(log.StandardLogger(), FineServ, validate)
+
+	NotificationRepo := repository.NewNotificationRepository(log.StandardLogger(), db)
+	NotificationServ := service.NewNotificationService(log.StandardLogger(), NotificationRepo)
+	NotificationHandler := controller.NewNotificationController(log.StandardLogger(), NotificationServ)
+
+	ReservServ := service.NewReservationService(log.StandardLogger(), Transactor, ReservRepo, MemberRepo, BookCopyRepo, BookRepo, LoanRepo, CalendarServ, BookCopyServ)
 	ReservHandler := controller.NewReservationController(log.StandardLogger(), ReservServ)
 
-	router := router.NewRouter(MemberHandler, AuthHandler, AuthorHandler, BookHandler, BookCopyHandler, LoanHandler, ReservHandler)
+	LoanServ := service.NewLoanServiceImpl(log.StandardLogger(), Transactor, LoanRepo, MemberRepo, BookCopyRepo, BookRepo, ReservRepo, ReservServ, FineServ, PolicyServ, CalendarServ, NotificationServ, BookCopyServ)
+	LoanHandler := controller.NewLoanController(log.StandardLogger(), LoanServ)
+
+	PatronSessionRepo := repository.NewPatronSessionRepository(log.StandardLogger(), db)
+	CirculationServ := service.NewCirculationService(log.StandardLogger(), PatronSessionRepo, MemberRepo, BookCopyRepo, LoanRepo, LoanServ)
+	CirculationHandler := controller.NewCirculationController(log.StandardLogger(), CirculationServ)
+
+	KioskRepo := repository.NewKioskRepository(log.StandardLogger(), db)
+	KioskServ := service.NewKioskService(log.StandardLogger(), KioskRepo)
+	KioskHandler := controller.NewKioskController(log.StandardLogger(), KioskServ, validate)
+
+	Scheduler := scheduler.New(log.StandardLogger(), repository.NewAdvisoryLocker(log.StandardLogger(), db), repository.NewJobRunRepository(log.StandardLogger(), db))
+	Scheduler.Register(scheduler.Job{
+		Name:     "mark-overdue-loans",
+		Interval: time.Duration(helper.GetEnvInt("JOB_OVERDUE_INTERVAL_MINUTES", 15)) * time.Minute,
+		Run:      LoanServ.MarkOverdue,
+	})
+	Scheduler.Register(scheduler.Job{
+		Name:     "accrue-overdue-fines",
+		Interval: time.Duration(helper.GetEnvInt("JOB_FINE_INTERVAL_MINUTES", 60)) * time.Minute,
+		Run:      FineServ.AccrueOverdue,
+	})
+	Scheduler.Register(scheduler.Job{
+		Name:     "expire-uncollected-holds",
+		Interval: time.Duration(helper.GetEnvInt("JOB_HOLD_INTERVAL_MINUTES", 30)) * time.Minute,
+		Run:      ReservServ.ExpireHolds,
+	})
+	Scheduler.Register(scheduler.Job{
+		Name:     "expire-stale-reservations",
+		Interval: time.Duration(helper.GetEnvInt("JOB_STALE_RESERVATION_INTERVAL_MINUTES", 60)) * time.Minute,
+		Run:      ReservServ.ExpireStale,
+	})
+	Scheduler.Register(scheduler.Job{
+		Name:     "recall-for-waiting-reservations",
+		Interval: time.Duration(helper.GetEnvInt("JOB_RECALL_INTERVAL_MINUTES", 60)) * time.Minute,
+		Run:      LoanServ.RecallForWaiting,
+	})
+	Scheduler.Register(scheduler.Job{
+		Name:     "declare-long-overdue-lost",
+		Interval: time.Duration(helper.GetEnvInt("JOB_LOST_INTERVAL_MINUTES", 1440)) * time.Minute,
+		Run:      LoanServ.DeclareLongOverdueLost,
+	})
+
+	// SIGINT or SIGTERM stops the job loops and the server; jobs already
+	// running finish before the database is closed
+	appCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	Scheduler.Start(appCtx)
+
+	router := router.NewRouter(MemberHandler, AuthHandler, AuthorHandler, BookHandler, BookCopyHandler, LoanHandler, ReservHandler, PermissionHandler, FineHandler, PolicyHandler, CalendarHandler, NotificationHandler, CirculationHandler, KioskHandler)
 
 	server := http.Server{
 		Addr:         ":8890",
@@ -75,13 +157,32 @@ func main() {
 		WriteTimeout: time.Second * 30,
 	}
 
-	log.Infof("Server Started at port %+v\n", ":8890")
+	go func() {
+		log.Infof("Server Started at port %+v\n", ":8890")
 
-	err = server.ListenAndServe()
+		err := server.ListenAndServe()
 
-	if err != nil {
-		wrapper := fmt.Errorf("cant start server : %w", err)
-		panic(wrapper)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			wrapper := fmt.Errorf("cant start server : %w", err)
+			panic(wrapper)
+		}
+	}()
+
+	<-appCtx.Done()
+	log.Info("App Shutting Down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Error("failed shutting down server")
+	}
+
+	Scheduler.Wait()
+
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
 	}
 
+	log.Info("App Stopped")
 }